@@ -0,0 +1,151 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+// BlockUser godoc
+// @Summary Block user
+// @Description Block the user with given username. They can no longer see the user's photos, comment on them, follow or mention the user, and follows between the two are removed.
+// @Tags block
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {string} string "Block success"
+// @Failure 400 "Cannot Block Yourself"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/block [post]
+func BlockUser(c *gin.Context) {
+	updateUserRelation(c, "block", events.UserBlocked, func(tx *gorm.DB, userID, otherID uint) (interface{}, error) {
+		Block := models.Block{BlockerID: userID, BlockedID: otherID}
+		added, err := addBlock(tx, Block)
+		if err != nil || !added {
+			return nil, err
+		}
+		return Block, nil
+	}, "User blocked")
+}
+
+// UnblockUser godoc
+// @Summary Unblock user
+// @Description Unblock the user with given username. Follows removed by the block are not restored.
+// @Tags block
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {string} string "Unblock success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/block [delete]
+func UnblockUser(c *gin.Context) {
+	updateUserRelation(c, "unblock", events.UserUnblocked, func(tx *gorm.DB, userID, otherID uint) (interface{}, error) {
+		Block := models.Block{}
+		result := tx.Clauses(clause.Returning{}).Where("blocker_id = ? AND blocked_id = ?", userID, otherID).Delete(&Block)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return nil, result.Error
+		}
+		return Block, nil
+	}, "User unblocked")
+}
+
+// MuteUser godoc
+// @Summary Mute user
+// @Description Mute the user with given username. Their photos are left out of the user's feeds and they are not told about it.
+// @Tags block
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {string} string "Mute success"
+// @Failure 400 "Cannot Mute Yourself"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/mute [post]
+func MuteUser(c *gin.Context) {
+	updateUserRelation(c, "mute", events.UserMuted, func(tx *gorm.DB, userID, otherID uint) (interface{}, error) {
+		Mute := models.Mute{MuterID: userID, MutedID: otherID}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&Mute)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return nil, result.Error
+		}
+		return Mute, nil
+	}, "User muted")
+}
+
+// UnmuteUser godoc
+// @Summary Unmute user
+// @Description Unmute the user with given username
+// @Tags block
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {string} string "Unmute success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/mute [delete]
+func UnmuteUser(c *gin.Context) {
+	updateUserRelation(c, "unmute", events.UserUnmuted, func(tx *gorm.DB, userID, otherID uint) (interface{}, error) {
+		Mute := models.Mute{}
+		result := tx.Clauses(clause.Returning{}).Where("muter_id = ? AND muted_id = ?", userID, otherID).Delete(&Mute)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return nil, result.Error
+		}
+		return Mute, nil
+	}, "User unmuted")
+}
+
+// updateUserRelation runs update in a transaction between the requesting
+// user and the user in the route, and responds with message when it
+// succeeds. update returns the record it changed, which is recorded as an
+// event of eventType, or nil when nothing changed.
+func updateUserRelation(c *gin.Context, action, eventType string, update func(tx *gorm.DB, userID, otherID uint) (interface{}, error), message string) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	if User.ID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "you can't " + action + " yourself",
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		record, err := update(tx.Debug(), userID, User.ID)
+		if err != nil || record == nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: eventType, ActorID: userID, Payload: record})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": message,
+	})
+}
@@ -0,0 +1,54 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/models"
+)
+
+// blockersSQL selects the IDs of the users who blocked someone.
+const blockersSQL = "SELECT blocker_id FROM blocks WHERE blocked_id = ?"
+
+// mutedUsersSQL selects the IDs of the users someone muted.
+const mutedUsersSQL = "SELECT muted_id FROM mutes WHERE muter_id = ?"
+
+// isBlocked reports whether the blocker has blocked the other user.
+func isBlocked(db *gorm.DB, blockerID, blockedID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Block{}).Where("blocker_id = ? AND blocked_id = ?", blockerID, blockedID).Count(&count).Error
+	return count > 0, err
+}
+
+// isBlockedEitherWay reports whether either of the two users blocked the
+// other.
+func isBlockedEitherWay(db *gorm.DB, userID, otherID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Block{}).Where("(blocker_id = ? AND blocked_id = ?) OR (blocker_id = ? AND blocked_id = ?)", userID, otherID, otherID, userID).Count(&count).Error
+	return count > 0, err
+}
+
+// addBlock stores a block and ends the follows and follow requests between
+// the two users in both directions. Blocking someone twice changes nothing.
+// It reports whether the block is new.
+func addBlock(tx *gorm.DB, block models.Block) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&block)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	_, err := removeFollow(tx, block.BlockedID, block.BlockerID)
+	if err != nil {
+		return true, err
+	}
+
+	_, err = removeFollow(tx, block.BlockerID, block.BlockedID)
+	return true, err
+}
+
+// unmutedPhotos leaves the photos of the users the viewer muted out of a
+// photo query.
+func unmutedPhotos(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("photos.user_id NOT IN (?)", gorm.Expr(mutedUsersSQL, viewerID))
+	}
+}
@@ -1,38 +1,73 @@
 package controllers
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"tesjwt.go/database"
+	"tesjwt.go/events"
 	"tesjwt.go/helpers"
 	"tesjwt.go/models"
+	"tesjwt.go/outbox"
 )
 
 type CreateCommentReq struct {
-	Message string `json:"message" form:"message" valid:"required~Message is required"`
-	PhotoID uint   `json:"photo_id" form:"photo_id" valid:"required~Photo is required"`
+	Message  string `json:"message" form:"message" valid:"required~Message is required"`
+	PhotoID  uint   `json:"photo_id" form:"photo_id" valid:"required~Photo is required"`
+	ParentID *uint  `json:"parent_id" form:"parent_id"`
 }
 
 type UpdateCommentReq struct {
 	Message string `json:"message" form:"message" valid:"required~Message is required"`
 }
 
+type CommentPage struct {
+	Data []models.Comment `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+type CommentCursorPage struct {
+	Data       []models.Comment `json:"data"`
+	NextCursor string           `json:"next_cursor,omitempty"`
+}
+
+// commentList is what the list of comments can be filtered and sorted by.
+var commentList = helpers.ListSpec{
+	Fields: map[string]helpers.ListField{
+		"id":         {Column: "comments.id", Type: helpers.FieldNumber, Sort: true},
+		"user_id":    {Column: "comments.user_id", Type: helpers.FieldNumber, Filter: true},
+		"photo_id":   {Column: "comments.photo_id", Type: helpers.FieldNumber, Filter: true},
+		"parent_id":  {Column: "comments.parent_id", Type: helpers.FieldNumber, Filter: true},
+		"created_at": {Column: "comments.created_at", Type: helpers.FieldTime, Filter: true, Sort: true},
+	},
+	Search:      []string{"comments.message"},
+	DefaultSort: "-id",
+	ID:          "comments.id",
+}
+
 // CreateComment godoc
 // @Summary Create comment
-// @Description Create comment for photo identified by given id
+// @Description Create comment for photo identified by given id, or a reply to another comment on it
 // @Tags comment
 // @Accept json
 // @Produce json
-// @Param photoId path int true "ID of the photo"
+// @Param photo_id query int true "ID of the photo"
 // @Param message query string true "message"
+// @Param parent_id query int false "ID of the comment to reply to"
 // @Security BearerAuth
-// @Success 201 {object} models.Comment "Create comment success"
+// @Success 201 {object} models.Comment "Create comment success, with status held when it waits for review by the owner of the photo"
 // @Failure 401 "Unauthorized"
+// @Failure 403 "Commenting Not Allowed"
 // @Failure 404 "Photo Not Found"
-// @Router /comment/{photoId} [post]
+// @Router /comment [post]
 func CreateComment(c *gin.Context) {
 	db := database.GetDB()
 	userData := c.MustGet("userData").(jwt.MapClaims)
@@ -51,16 +86,106 @@ func CreateComment(c *gin.Context) {
 		Message: req.Message,
 	}
 
-	err := db.First(&models.Photo{}, req.PhotoID).Error
+	Photo := models.Photo{}
+	err := db.Scopes(viewablePhotos(userID)).First(&Photo, req.PhotoID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo not found",
+		})
+		return
+	}
+
+	var settings models.CommentSettings
+	if err == nil {
+		settings, err = findCommentSettings(db, Photo.ID)
+	}
+
+	var reason string
+	if err == nil {
+		reason, err = commentRestriction(db, Photo, settings, userID)
+	}
 	if err != nil {
 		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
-			"message": "photo not found",
-			"error":   err.Error(),
+			"error":   "Internal Server Error",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if reason != "" {
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": reason,
+		})
+		return
+	}
+
+	// Comments matching the blocklist of the owner wait for their review.
+	if _, found := helpers.MatchKeyword(req.Message, settings.Blocklist); found && Photo.UserID != userID {
+		Comment.Status = models.CommentHeld
+	}
+
+	if req.ParentID != nil {
+		parent := models.Comment{}
+		err = db.Scopes(visibleComments(userID)).Select("comments.id", "comments.photo_id", "comments.depth").First(&parent, *req.ParentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+				"error":   "Data Not Found",
+				"message": "parent comment not found",
+			})
+			return
+		}
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{
+				"error":   "Internal Server Error",
+				"message": err.Error(),
+			})
+			return
+		}
+
+		if parent.PhotoID != req.PhotoID {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": "parent comment belongs to another photo",
+			})
+			return
+		}
+
+		if parent.Depth >= models.MaxCommentDepth {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": fmt.Sprintf("replies can only be nested %d levels deep", models.MaxCommentDepth),
+			})
+			return
+		}
+
+		Comment.ParentID = &parent.ID
+		Comment.Depth = parent.Depth + 1
+	}
+
+	Comment.Mentions, err = resolveMentions(db, userID, Comment.Message)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": err.Error(),
 		})
 		return
 	}
 
-	err = db.Debug().Create(&Comment).Error
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&Comment).Error
+		if err != nil {
+			return err
+		}
+
+		// Held comments are announced once the owner approves them.
+		if Comment.Status == models.CommentHeld {
+			return nil
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.CommentCreated, ActorID: userID, Payload: Comment})
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -74,22 +199,25 @@ func CreateComment(c *gin.Context) {
 
 // UpdateComment godoc
 // @Summary Update comment
-// @Description Update comment identified by given id
+// @Description Update comment identified by given id. The previous message is kept in the edit history of the comment. Edits that match the blocklist of the owner of the photo are held for their review.
 // @Tags comment
 // @Accept json
 // @Produce json
-// @Param commentId path int true "ID of the comment"
+// @Param commentID path int true "ID of the comment"
 // @Security BearerAuth
 // @Success 200 {object} models.Comment "Update comment success"
 // @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
 // @Failure 404 "Comment Not Found"
 // @Router /comment/{commentID} [put]
 func UpdateComment(c *gin.Context) {
 	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
 	contentType := helpers.GetContentType(c)
 	req := UpdateCommentReq{}
 
 	CommentID, _ := strconv.Atoi(c.Param("commentID"))
+	userID := uint(userData["id"].(float64))
 
 	if contentType == appJSON {
 		c.ShouldBindJSON(&req)
@@ -97,11 +225,86 @@ func UpdateComment(c *gin.Context) {
 		c.ShouldBind(&req)
 	}
 
-	Comment := models.Comment{
-		Message: req.Message,
-	}
+	Comment := models.Comment{}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&Comment, CommentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return missedOwnedRow(tx, &models.Comment{}, uint(CommentID))
+		}
+		if err != nil {
+			return err
+		}
+
+		// The previous message is kept as a revision so edits made after
+		// others replied can still be looked up.
+		edited := Comment.Message != req.Message
+		if edited {
+			revision := models.CommentRevision{
+				CommentID: Comment.ID,
+				Message:   Comment.Message,
+				EditorID:  userID,
+			}
+
+			now := time.Now()
+			Comment.Message = req.Message
+			Comment.EditedAt = &now
+			Comment.RevisionCount++
+
+			// Edits are held the same way new comments are when they match
+			// the blocklist of the owner of the photo.
+			if Comment.Status == models.CommentVisible {
+				Photo := models.Photo{}
+				err = tx.Select("id", "user_id").First(&Photo, Comment.PhotoID).Error
+				if err != nil {
+					return err
+				}
+
+				settings, err := findCommentSettings(tx, Photo.ID)
+				if err != nil {
+					return err
+				}
 
-	err := db.Debug().Model(&Comment).Where("id = ?", CommentID).Updates(models.Comment{Message: req.Message}).Error
+				if _, found := helpers.MatchKeyword(Comment.Message, settings.Blocklist); found && Photo.UserID != userID {
+					Comment.Status = models.CommentHeld
+				}
+			}
+
+			result := tx.Debug().Model(&Comment).Updates(models.Comment{Message: Comment.Message, EditedAt: Comment.EditedAt, RevisionCount: Comment.RevisionCount, Status: Comment.Status})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return gorm.ErrRecordNotFound
+			}
+
+			err = tx.Debug().Create(&revision).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		Comment.Mentions, err = replaceMentions(tx, models.TargetComment, Comment.ID, Comment.UserID, Comment.Message)
+		if err != nil || !edited {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.CommentUpdated, ActorID: userID, Payload: Comment})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "comment doesn't exist",
+		})
+		return
+	}
+	if errors.Is(err, errNotOwner) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -111,20 +314,25 @@ func UpdateComment(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusOK, gin.H{
-		"Message": "Comment Updated",
+		"Message":        "Comment Updated",
+		"mentions":       Comment.Mentions,
+		"edited_at":      Comment.EditedAt,
+		"revision_count": Comment.RevisionCount,
+		"status":         Comment.Status,
 	})
 }
 
 // DeleteComment godoc
 // @Summary Delete comment
-// @Description Delete comment identified by given ID
+// @Description Delete comment identified by given ID together with its replies
 // @Tags comment
 // @Accept json
 // @Produce json
-// @Param commentId path int true "ID of the comment"
+// @Param commentID path int true "ID of the comment"
 // @Security BearerAuth
 // @Success 200 {string} string "Delete comment success"
 // @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
 // @Failure 404 "Comment Not Found"
 // @Router /comment/{commentID} [delete]
 func DeleteComment(c *gin.Context) {
@@ -145,7 +353,46 @@ func DeleteComment(c *gin.Context) {
 	Comment.UserID = userID
 	Comment.ID = uint(CommentID)
 
-	err := db.Model(&Comment).Where("id = ?", CommentID).Delete(&Comment).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Replies go to the trash together with the comment they answer and
+		// share its deletion time, which is how they are found again on
+		// restore.
+		err := tx.Debug().Clauses(clause.Locking{Strength: "UPDATE"}).Select("id").Where("user_id = ?", userID).First(&models.Comment{}, CommentID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return missedOwnedRow(tx, &models.Comment{}, uint(CommentID))
+		}
+		if err != nil {
+			return err
+		}
+
+		deleted := []models.Comment{}
+		err = tx.Debug().Model(&deleted).Clauses(clause.Returning{}).Scopes(inCommentThreads(uint(CommentID))).UpdateColumn("deleted_at", time.Now()).Error
+		if err != nil {
+			return err
+		}
+
+		for _, comment := range deleted {
+			if comment.ID == uint(CommentID) {
+				return outbox.Record(tx, events.Event{Type: events.CommentDeleted, ActorID: userID, Payload: comment})
+			}
+		}
+
+		return gorm.ErrRecordNotFound
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "comment doesn't exist",
+		})
+		return
+	}
+	if errors.Is(err, errNotOwner) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -159,37 +406,52 @@ func DeleteComment(c *gin.Context) {
 	})
 }
 
-// GetAllCommentsForPhoto godoc
-// @Summary Get all comments for specific photo
-// @Description Get all comments for photo with given id
+// GetComment godoc
+// @Summary Get comment
+// @Description Get comment identified by given id, with the relations asked for in include. Embedded users only show their profile.
 // @Tags comment
 // @Accept json
 // @Produce json
-// @Param photoId path int true "ID of the photo"
+// @Param commentID path int true "ID of the comment"
+// @Param fields query string false "fields of the comment to return, separated by commas"
+// @Param include query string false "user, photo or photo.user, separated by commas"
 // @Security BearerAuth
-// @Success 200 {object} []models.Comment "Get all comments success"
+// @Success 200 {object} models.Comment "Get comment success"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
-// @Failure 404 "Comments Not Found"
-// @Router /comment/{photoID} [get]
+// @Failure 404 "Comment Not Found"
+// @Router /comment/{commentID} [get]
 func FindCommentById(c *gin.Context) {
 	db := database.GetDB()
 	userData := c.MustGet("userData").(jwt.MapClaims)
-	contentType := helpers.GetContentType(c)
 	Comment := models.Comment{}
 
 	CommentID, _ := strconv.Atoi(c.Param("commentID"))
 	userID := uint(userData["id"].(float64))
 
-	if contentType == appJSON {
-		c.ShouldBindJSON(&Comment)
-	} else {
-		c.ShouldBind(&Comment)
+	sparse, err := helpers.ParseSparse(c, models.Comment{}, commentIncludes(userID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
 	}
 
-	Comment.UserID = userID
-	Comment.ID = uint(CommentID)
+	err = db.Scopes(visibleComments(userID), sparse.Preload).Preload("Mentions").First(&Comment, CommentID).Error
 
-	err := db.Model(&Comment).Where("id = ?", CommentID).Find(&Comment).Error
+	var reactions map[uint]map[string]int
+	if err == nil {
+		reactions, err = findReactionCounts(db, models.TargetComment, []uint{Comment.ID})
+		Comment.Reactions = reactions[Comment.ID]
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "comment doesn't exist",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -198,24 +460,39 @@ func FindCommentById(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, Comment)
+	sparse.JSON(c, http.StatusOK, Comment)
 }
 
 // GetAllComments godoc
 // @Summary Get all comments
-// @Description Get all comments in mygram
+// @Description Get all comments in mygram, newest first. Pass the next_cursor of a page as after to get the page that follows it, which the Link header also points at. Cursors only work with the filters and sort they were made with.
 // @Tags comment
 // @Accept json
 // @Produce json
+// @Param user_id query string false "IDs of the authors, separated by commas"
+// @Param photo_id query string false "IDs of the photos, separated by commas"
+// @Param parent_id query string false "IDs of the comments replied to, separated by commas"
+// @Param created_after query string false "date or RFC 3339 time"
+// @Param created_before query string false "date or RFC 3339 time"
+// @Param q query string false "text to look for in the message"
+// @Param sort query string false "id or created_at, separated by commas and prefixed with - to sort descending, defaults to -id"
+// @Param fields query string false "fields of the comments to return, separated by commas"
+// @Param include query string false "user, photo or photo.user, separated by commas"
+// @Param after query string false "next_cursor of the previous page"
+// @Param limit query int false "comments per page, at most 100"
 // @Security BearerAuth
-// @Success 200 {object} []models.Comment "Get all comments success"
+// @Success 200 {object} CommentCursorPage "Get all comments success"
+// @Header 200 {string} Link "link to the next page"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
 // @Failure 404 "Comments Not Found"
 // @Router /comment [get]
 func FindAllComment(c *gin.Context) {
 	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
 	contentType := helpers.GetContentType(c)
 	Comment := []models.Comment{}
+	userID := uint(userData["id"].(float64))
 
 	if contentType == appJSON {
 		c.ShouldBindJSON(&Comment)
@@ -223,7 +500,185 @@ func FindAllComment(c *gin.Context) {
 		c.ShouldBind(&Comment)
 	}
 
-	err := db.Debug().Find(&Comment).Error
+	list, err := helpers.ParseList(c, commentList)
+
+	var sparse helpers.Sparse
+	if err == nil {
+		sparse, err = helpers.ParseSparse(c, models.Comment{}, commentIncludes(userID))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	keyset := helpers.GetKeyset(c, list.Sort...)
+	next, err := keyset.Find(db.Debug().Scopes(visibleComments(userID), list.Filter, sparse.Preload).Preload("Mentions"), &Comment)
+	if err == nil {
+		err = attachCommentReactions(db, Comment)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	helpers.SetNextLink(c, next, keyset.Limit)
+	sparse.JSON(c, http.StatusOK, CommentCursorPage{
+		Data:       Comment,
+		NextCursor: next,
+	})
+}
+
+// GetPhotoComments godoc
+// @Summary Get comments of a photo
+// @Description Get the comment threads of a photo a page at a time, with the author of every comment. With thread=nested (the default) replies are nested under the comment they answer and reply_count is the number of direct replies. With thread=flat every reply of a thread is listed oldest first under its top-level comment, whose reply_count is then the size of the whole thread. Ordering applies to top-level comments; top puts the ones with the most replies first. Hidden and held comments are only listed for their author and the owner of the photo.
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param thread query string false "nested or flat"
+// @Param order query string false "oldest, newest or top"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "threads per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} CommentPage "Get photo comments success"
+// @Failure 400 "Unknown Thread Mode Or Order"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/comments [get]
+func FindCommentByPhoto(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Comment := []models.Comment{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+	thread := c.DefaultQuery("thread", "nested")
+
+	if thread != "nested" && thread != "flat" {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "thread has to be nested or flat",
+		})
+		return
+	}
+
+	order, ok := commentOrders[c.DefaultQuery("order", "oldest")]
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "order has to be oldest, newest or top",
+		})
+		return
+	}
+
+	Photo := models.Photo{}
+	err := db.Scopes(viewablePhotos(userID)).Select("id", "user_id").First(&Photo, PhotoID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+
+	var total int64
+	threadIDs := []uint{}
+	topLevel := db.Debug().Model(&models.Comment{}).Scopes(visibleComments(userID)).Where("photo_id = ? AND parent_id IS NULL", PhotoID).Session(&gorm.Session{})
+	if err == nil {
+		err = topLevel.Count(&total).Error
+	}
+	if err == nil {
+		err = topLevel.Order(order).Scopes(page.Paginate).Pluck("id", &threadIDs).Error
+	}
+	if err == nil && len(threadIDs) > 0 {
+		err = db.Debug().Preload("Mentions").Scopes(inCommentThreads(threadIDs...)).Order("id").Find(&Comment).Error
+	}
+	if err == nil {
+		Comment = pruneHiddenComments(Comment, Photo.UserID, userID)
+		err = attachCommentAuthors(db, Comment)
+	}
+	if err == nil {
+		err = attachCommentReactions(db, Comment)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	threads := nestComments(Comment)
+	if thread == "flat" {
+		threads = flattenComments(Comment)
+	}
+	sortThreads(threads, threadIDs)
+
+	c.JSON(http.StatusOK, CommentPage{
+		Data:  threads,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// GetCommentRevisions godoc
+// @Summary Get comment edit history
+// @Description Get the previous messages of a comment, oldest first. Only the author of the comment and moderators can see them.
+// @Tags comment
+// @Accept json
+// @Produce json
+// @Param commentID path int true "ID of the comment"
+// @Security BearerAuth
+// @Success 200 {object} []models.CommentRevision "Get comment revisions success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Comment Not Found"
+// @Router /comment/{commentID}/revisions [get]
+func FindCommentRevision(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Comment := models.Comment{}
+	User := models.User{}
+	CommentRevision := []models.CommentRevision{}
+
+	CommentID, _ := strconv.Atoi(c.Param("commentID"))
+	userID := uint(userData["id"].(float64))
+
+	err := db.Select("id", "user_id").First(&Comment, CommentID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "comment doesn't exist",
+		})
+		return
+	}
+	if err == nil {
+		err = db.Select("id", "role").First(&User, userID).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	if Comment.UserID != userID && !User.IsModerator() {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "only the author and moderators can see the edit history of a comment",
+		})
+		return
+	}
+
+	err = db.Debug().Where("comment_id = ?", CommentID).Order("id").Find(&CommentRevision).Error
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -232,5 +687,5 @@ func FindAllComment(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, Comment)
+	c.JSON(http.StatusOK, CommentRevision)
 }
@@ -0,0 +1,507 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type CreateConversationReq struct {
+	Usernames []string `json:"usernames" form:"usernames"`
+}
+
+type ConversationPage struct {
+	Data []models.Conversation `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+type MessagePage struct {
+	Data    []models.Message            `json:"data"`
+	Members []models.ConversationMember `json:"members"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+// CreateConversation godoc
+// @Summary Create conversation
+// @Description Start a conversation with one or more users. Starting a conversation with a single user that already talks with the user returns their existing conversation. Users can't message anyone they blocked or who blocked them, and private accounts only get messages from their followers.
+// @Tags conversation
+// @Accept json
+// @Produce json
+// @Param conversation body CreateConversationReq true "Usernames of the other members"
+// @Security BearerAuth
+// @Success 201 {object} models.Conversation "Create conversation success"
+// @Success 200 {object} models.Conversation "Existing conversation"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Messaging Not Allowed"
+// @Failure 404 "User Not Found"
+// @Router /conversations [post]
+func CreateConversation(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := CreateConversationReq{}
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	names := []string{}
+	seen := map[string]bool{}
+	for _, username := range req.Usernames {
+		name := strings.ToLower(strings.TrimPrefix(strings.TrimSpace(username), "@"))
+		if name != "" && !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	User := []models.User{}
+	err := db.Where("LOWER(username) IN ?", names).Order("id").Find(&User).Error
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	found := map[string]bool{}
+	others := []models.User{}
+	for _, user := range User {
+		name := strings.ToLower(user.Username)
+		if user.ID != userID && !found[name] {
+			others = append(others, user)
+		}
+		found[name] = true
+	}
+
+	for _, name := range names {
+		if !found[name] {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Data Not Found",
+				"message": "user " + name + " doesn't exist",
+			})
+			return
+		}
+	}
+
+	if len(others) == 0 || len(others)+1 > models.MaxConversationMembers {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": fmt.Sprintf("a conversation needs between 1 and %d other users", models.MaxConversationMembers-1),
+		})
+		return
+	}
+
+	reason, err := messagingRestriction(db, userID, others)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if reason != "" {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": reason,
+		})
+		return
+	}
+
+	Conversation := models.Conversation{}
+	var existing bool
+	if len(others) == 1 {
+		Conversation, err = findDirectConversation(db, userID, others[0].ID)
+		existing = err == nil
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = nil
+		}
+	}
+
+	if err == nil && !existing {
+		Conversation = models.Conversation{
+			CreatorID: userID,
+			IsGroup:   len(others) > 1,
+			Members:   []models.ConversationMember{{UserID: userID}},
+		}
+		for _, user := range others {
+			Conversation.Members = append(Conversation.Members, models.ConversationMember{UserID: user.ID})
+		}
+
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Debug().Create(&Conversation).Error
+			if err != nil {
+				return err
+			}
+
+			return outbox.Record(tx, events.Event{Type: events.ConversationCreated, ActorID: userID, Payload: Conversation})
+		})
+	}
+
+	Conversations := []models.Conversation{Conversation}
+	if err == nil {
+		err = attachConversationDetails(db, Conversations, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	status := http.StatusCreated
+	if existing {
+		status = http.StatusOK
+	}
+
+	c.JSON(status, Conversations[0])
+}
+
+// GetConversations godoc
+// @Summary Get conversations
+// @Description Get the conversations of the user, the ones with the latest messages first, with their members, last message and number of unread messages
+// @Tags conversation
+// @Accept json
+// @Produce json
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "conversations per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} ConversationPage "Get conversations success"
+// @Failure 401 "Unauthorized"
+// @Router /conversations [get]
+func FindConversation(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Conversation := []models.Conversation{}
+
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	query := db.Model(&models.Conversation{}).Where("id IN (SELECT conversation_id FROM conversation_members WHERE user_id = ?)", userID).Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err == nil {
+		err = query.Debug().Order("COALESCE(last_message_at, created_at) DESC, id DESC").Scopes(page.Paginate).Find(&Conversation).Error
+	}
+	if err == nil {
+		err = attachConversationDetails(db, Conversation, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ConversationPage{
+		Data:  Conversation,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// GetMessages godoc
+// @Summary Get messages
+// @Description Get the messages of a conversation of the user, newest first, with the last message every member read. Shared photos the user can't see are left out of their messages.
+// @Tags conversation
+// @Accept json
+// @Produce json
+// @Param conversationID path int true "ID of the conversation"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "messages per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} MessagePage "Get messages success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Conversation Not Found"
+// @Router /conversations/{conversationID}/messages [get]
+func FindMessage(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Message := []models.Message{}
+
+	ConversationID, _ := strconv.Atoi(c.Param("conversationID"))
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	Conversation, err := findConversation(db, uint(ConversationID), userID)
+	if abortConversationNotFound(c, err) {
+		return
+	}
+
+	var total int64
+	err = db.Model(&models.Message{}).Where("conversation_id = ?", Conversation.ID).Count(&total).Error
+	if err == nil {
+		err = db.Debug().Where("conversation_id = ?", Conversation.ID).Order("id DESC").Scopes(page.Paginate).Find(&Message).Error
+	}
+	if err == nil {
+		err = attachMessagePhotos(db, Message, userID)
+	}
+
+	Conversations := []models.Conversation{Conversation}
+	if err == nil {
+		err = attachConversationDetails(db, Conversations, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, MessagePage{
+		Data:    Message,
+		Members: Conversations[0].Members,
+		Page:    page,
+		Total:   total,
+	})
+}
+
+// CreateMessage godoc
+// @Summary Send message
+// @Description Send a message to a conversation of the user. A message can share a photo the user can see by its id, with or without a body.
+// @Tags conversation
+// @Accept json
+// @Produce json
+// @Param conversationID path int true "ID of the conversation"
+// @Param body query string false "text of the message, at most 2000 characters"
+// @Param photo_id query int false "ID of the photo to share"
+// @Security BearerAuth
+// @Success 201 {object} models.Message "Send message success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Messaging Not Allowed"
+// @Failure 404 "Conversation Not Found"
+// @Router /conversations/{conversationID}/messages [post]
+func CreateMessage(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	Message := models.Message{}
+
+	ConversationID, _ := strconv.Atoi(c.Param("conversationID"))
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&Message)
+	} else {
+		c.ShouldBind(&Message)
+	}
+
+	Conversation, err := findConversation(db, uint(ConversationID), userID)
+	if abortConversationNotFound(c, err) {
+		return
+	}
+
+	Message = models.Message{
+		ConversationID: Conversation.ID,
+		SenderID:       userID,
+		Body:           strings.TrimSpace(Message.Body),
+		PhotoID:        Message.PhotoID,
+	}
+
+	_, err = govalidator.ValidateStruct(Message)
+	if err == nil && Message.Body == "" && Message.PhotoID == nil {
+		err = errors.New("a message needs a body or a photo")
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	// A one-to-one conversation ends once the other member could no longer
+	// be messaged, for instance after a block or when they went private and
+	// the user doesn't follow them. Group members keep talking among the
+	// others.
+	if !Conversation.IsGroup {
+		Other := models.User{}
+		err = db.Select("id", "username", "is_private").Where("id IN (SELECT user_id FROM conversation_members WHERE conversation_id = ? AND user_id <> ?)", Conversation.ID, userID).First(&Other).Error
+
+		var reason string
+		if err == nil {
+			reason, err = messagingRestriction(db, userID, []models.User{Other})
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		if reason != "" {
+			c.JSON(http.StatusForbidden, gin.H{
+				"error":   "Forbidden",
+				"message": reason,
+			})
+			return
+		}
+	}
+
+	if Message.PhotoID != nil {
+		Photo := models.Photo{}
+		err = db.Scopes(viewablePhotos(userID)).First(&Photo, *Message.PhotoID).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			c.JSON(http.StatusNotFound, gin.H{
+				"error":   "Data Not Found",
+				"message": "photo doesn't exist",
+			})
+			return
+		}
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{
+				"error":   "Bad Request",
+				"message": err.Error(),
+			})
+			return
+		}
+		Message.Photo = &Photo
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Debug()
+		err := tx.Omit("Photo", "Conversation").Create(&Message).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Conversation{}).Where("id = ?", Conversation.ID).UpdateColumn("last_message_at", Message.CreatedAt).Error
+		if err != nil {
+			return err
+		}
+
+		_, err = markConversationRead(tx, Conversation.ID, userID, Message.ID)
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.MessageCreated, ActorID: userID, Payload: Message})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Message)
+}
+
+// ReadConversation godoc
+// @Summary Mark conversation read
+// @Description Mark every message of a conversation of the user as read. The other members see the last message the user read.
+// @Tags conversation
+// @Accept json
+// @Produce json
+// @Param conversationID path int true "ID of the conversation"
+// @Security BearerAuth
+// @Success 200 {object} models.ConversationMember "Mark conversation read success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Conversation Not Found"
+// @Router /conversations/{conversationID}/read [post]
+func ReadConversation(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	ConversationMember := models.ConversationMember{}
+
+	ConversationID, _ := strconv.Atoi(c.Param("conversationID"))
+	userID := uint(userData["id"].(float64))
+
+	Conversation, err := findConversation(db, uint(ConversationID), userID)
+	if abortConversationNotFound(c, err) {
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		var lastMessageID uint
+		err := tx.Model(&models.Message{}).Select("COALESCE(MAX(id), 0)").Where("conversation_id = ?", Conversation.ID).Scan(&lastMessageID).Error
+		if err != nil {
+			return err
+		}
+
+		var moved bool
+		if lastMessageID > 0 {
+			moved, err = markConversationRead(tx.Debug(), Conversation.ID, userID, lastMessageID)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = tx.Where("conversation_id = ? AND user_id = ?", Conversation.ID, userID).First(&ConversationMember).Error
+		if err != nil || !moved {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.ConversationRead, ActorID: userID, Payload: ConversationMember})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, ConversationMember)
+}
+
+// markConversationRead moves the read receipt of a member up to the given
+// message. Receipts never move back. It reports whether the receipt moved.
+func markConversationRead(tx *gorm.DB, conversationID, userID, messageID uint) (bool, error) {
+	result := tx.Model(&models.ConversationMember{}).
+		Where("conversation_id = ? AND user_id = ? AND COALESCE(last_read_message_id, 0) < ?", conversationID, userID, messageID).
+		UpdateColumns(map[string]interface{}{
+			"last_read_message_id": messageID,
+			"last_read_at":         time.Now(),
+		})
+
+	return result.RowsAffected > 0, result.Error
+}
+
+// abortConversationNotFound responds to a failed conversation lookup. It
+// reports whether the request was aborted.
+func abortConversationNotFound(c *gin.Context, err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "conversation doesn't exist",
+		})
+		return true
+	}
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return true
+	}
+
+	return false
+}
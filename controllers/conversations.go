@@ -0,0 +1,160 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// unreadMessagesSQL counts the messages of a conversation a member hasn't
+// read yet, leaving out their own.
+const unreadMessagesSQL = `SELECT COUNT(*) FROM messages WHERE messages.conversation_id = conversation_members.conversation_id
+	AND messages.id > COALESCE(conversation_members.last_read_message_id, 0) AND messages.sender_id <> conversation_members.user_id`
+
+// messagingRestriction explains why the sender may not message one of the
+// users. It returns an empty string when they may message all of them.
+// Users can't message anyone they blocked or who blocked them, and private
+// accounts only get messages from their followers.
+func messagingRestriction(db *gorm.DB, senderID uint, users []models.User) (string, error) {
+	for _, user := range users {
+		blocked, err := isBlockedEitherWay(db, senderID, user.ID)
+		if err != nil {
+			return "", err
+		}
+		if blocked {
+			return "you can't message " + user.Username, nil
+		}
+
+		if !user.IsPrivate {
+			continue
+		}
+
+		following, err := isFollowing(db, senderID, user.ID)
+		if err != nil {
+			return "", err
+		}
+		if !following {
+			return user.Username + " only gets messages from followers", nil
+		}
+	}
+
+	return "", nil
+}
+
+// findConversation loads a conversation the user takes part in. It fails
+// with gorm.ErrRecordNotFound for conversations of other users.
+func findConversation(db *gorm.DB, conversationID, userID uint) (models.Conversation, error) {
+	conversation := models.Conversation{}
+	err := db.Where("id IN (SELECT conversation_id FROM conversation_members WHERE user_id = ?)", userID).First(&conversation, conversationID).Error
+	return conversation, err
+}
+
+// findDirectConversation loads the conversation between just the two users.
+// It fails with gorm.ErrRecordNotFound when they haven't talked yet.
+func findDirectConversation(db *gorm.DB, userID, otherID uint) (models.Conversation, error) {
+	conversation := models.Conversation{}
+	err := db.Where("NOT is_group").
+		Where("id IN (SELECT conversation_id FROM conversation_members WHERE user_id = ?)", userID).
+		Where("id IN (SELECT conversation_id FROM conversation_members WHERE user_id = ?)", otherID).
+		First(&conversation).Error
+	return conversation, err
+}
+
+// attachConversationDetails fills in the members, the last message and the
+// number of messages the viewer hasn't read of every conversation.
+func attachConversationDetails(db *gorm.DB, conversations []models.Conversation, viewerID uint) error {
+	if len(conversations) == 0 {
+		return nil
+	}
+
+	conversationIDs := make([]uint, 0, len(conversations))
+	for _, conversation := range conversations {
+		conversationIDs = append(conversationIDs, conversation.ID)
+	}
+
+	members := []models.ConversationMember{}
+	err := db.Where("conversation_id IN ?", conversationIDs).Order("created_at, user_id").Find(&members).Error
+	if err != nil {
+		return err
+	}
+
+	userIDs := make([]uint, 0, len(members))
+	for _, member := range members {
+		userIDs = append(userIDs, member.UserID)
+	}
+	users, err := findUserSummaries(db, userIDs)
+	if err != nil {
+		return err
+	}
+
+	messages := []models.Message{}
+	err = db.Where("id IN (SELECT MAX(id) FROM messages WHERE conversation_id IN ? GROUP BY conversation_id)", conversationIDs).Find(&messages).Error
+	if err == nil {
+		err = attachMessagePhotos(db, messages, viewerID)
+	}
+	if err != nil {
+		return err
+	}
+
+	unread := []struct {
+		ConversationID uint
+		Count          int64
+	}{}
+	err = db.Model(&models.ConversationMember{}).Select("conversation_id", "("+unreadMessagesSQL+") AS count").
+		Where("conversation_id IN ? AND user_id = ?", conversationIDs, viewerID).Scan(&unread).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range conversations {
+		conversation := &conversations[i]
+		conversation.Members = nil
+		for _, member := range members {
+			if member.ConversationID == conversation.ID {
+				member.User = users[member.UserID]
+				conversation.Members = append(conversation.Members, member)
+			}
+		}
+		for j := range messages {
+			if messages[j].ConversationID == conversation.ID {
+				conversation.LastMessage = &messages[j]
+			}
+		}
+		for _, count := range unread {
+			if count.ConversationID == conversation.ID {
+				conversation.UnreadCount = count.Count
+			}
+		}
+	}
+
+	return nil
+}
+
+// attachMessagePhotos fills in the photos shared in the messages, as far as
+// the viewer may see them.
+func attachMessagePhotos(db *gorm.DB, messages []models.Message, viewerID uint) error {
+	photoIDs := []uint{}
+	for _, message := range messages {
+		if message.PhotoID != nil {
+			photoIDs = append(photoIDs, *message.PhotoID)
+		}
+	}
+	if len(photoIDs) == 0 {
+		return nil
+	}
+
+	photos := []models.Photo{}
+	err := db.Scopes(viewablePhotos(viewerID)).Where("photos.id IN ?", photoIDs).Find(&photos).Error
+	if err != nil {
+		return err
+	}
+
+	for i := range messages {
+		for j := range photos {
+			if messages[i].PhotoID != nil && *messages[i].PhotoID == photos[j].ID {
+				messages[i].Photo = &photos[j]
+			}
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"tesjwt.go/database"
+	"tesjwt.go/feed"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+)
+
+type FeedPage struct {
+	Data       []models.Photo `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// GetFeed godoc
+// @Summary Get feed
+// @Description Get the photos of the users the user follows and hasn't muted, newest first. Pass the next_cursor of a page as after to get the page that follows it.
+// @Tags feed
+// @Accept json
+// @Produce json
+// @Param after query string false "next_cursor of the previous page"
+// @Param limit query int false "photos per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} FeedPage "Get feed success"
+// @Header 200 {string} Link "link to the next page"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Router /feed [get]
+func FindFeed(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Photo := []models.Photo{}
+
+	userID := uint(userData["id"].(float64))
+	keyset := helpers.GetKeyset(c, helpers.SortKey{Column: "photos.published_at", Desc: true}, helpers.SortKey{Column: "photos.id", Desc: true})
+
+	query := db.Debug().Scopes(feed.Photos(userID), listablePhotos(userID), unmutedPhotos(userID)).Preload("Mentions")
+	next, err := keyset.Find(query, &Photo)
+	if err == nil {
+		err = attachPhotoReactions(db, Photo)
+	}
+	if err == nil {
+		err = attachLikedByMe(db, userID, Photo)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	helpers.SetNextLink(c, next, keyset.Limit)
+	c.JSON(http.StatusOK, FeedPage{
+		Data:       Photo,
+		NextCursor: next,
+	})
+}
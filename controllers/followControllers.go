@@ -0,0 +1,489 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type UpdatePrivacyReq struct {
+	IsPrivate bool `json:"is_private" form:"is_private"`
+}
+
+type Profile struct {
+	ID             uint   `json:"id"`
+	Username       string `json:"username"`
+	IsPrivate      bool   `json:"is_private"`
+	FollowerCount  int    `json:"follower_count"`
+	FollowingCount int    `json:"following_count"`
+	FollowStatus   string `json:"follow_status,omitempty"`
+}
+
+// GetProfile godoc
+// @Summary Get profile
+// @Description Get the profile of the user with given username, with follower and following counts. follow_status tells whether the requesting user follows them or asked to.
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {object} Profile "Get profile success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username} [get]
+func FindProfile(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	Profile := Profile{
+		ID:             User.ID,
+		Username:       User.Username,
+		IsPrivate:      User.IsPrivate,
+		FollowerCount:  User.FollowerCount,
+		FollowingCount: User.FollowingCount,
+	}
+
+	err = db.Model(&models.Follow{}).Select("status").Where("follower_id = ? AND followee_id = ?", userID, User.ID).Scan(&Profile.FollowStatus).Error
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Profile)
+}
+
+// FollowUser godoc
+// @Summary Follow user
+// @Description Follow the user with given username. Following a private account sends a follow request that the account owner has to approve.
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {object} interface{} "Follow status, accepted or pending"
+// @Failure 400 "Cannot Follow Yourself"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Blocked By User"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/follow [post]
+func FollowUser(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	if User.ID == userID {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "you can't follow yourself",
+		})
+		return
+	}
+
+	blocked, err := isBlocked(db, User.ID, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+	if blocked {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "you can't follow this user",
+		})
+		return
+	}
+
+	Follow := models.Follow{
+		FollowerID: userID,
+		FolloweeID: User.ID,
+		Status:     models.FollowAccepted,
+	}
+	if User.IsPrivate {
+		Follow.Status = models.FollowPending
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		created, err := addFollow(tx.Debug(), Follow)
+		if err != nil {
+			return err
+		}
+
+		err = tx.Model(&models.Follow{}).Select("status").Where("follower_id = ? AND followee_id = ?", userID, User.ID).Scan(&Follow.Status).Error
+		if err != nil || !created {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.UserFollowed, ActorID: userID, Payload: Follow})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"status": Follow.Status,
+	})
+}
+
+// UnfollowUser godoc
+// @Summary Unfollow user
+// @Description Stop following the user with given username, or withdraw a follow request
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Security BearerAuth
+// @Success 200 {string} string "Unfollow success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/follow [delete]
+func UnfollowUser(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		removed, err := removeFollow(tx.Debug(), userID, User.ID)
+		if err != nil || !removed {
+			return err
+		}
+
+		Follow := models.Follow{FollowerID: userID, FolloweeID: User.ID}
+		return outbox.Record(tx, events.Event{Type: events.UserUnfollowed, ActorID: userID, Payload: Follow})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Unfollowed",
+	})
+}
+
+// GetFollowers godoc
+// @Summary Get followers
+// @Description Get the users following the user with given username, most recent first. The followers of private accounts are only shown to their followers.
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "users per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} UserPage "Get followers success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Private Account"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/followers [get]
+func FindFollower(c *gin.Context) {
+	findFollowUsers(c, "follower_id", "followee_id")
+}
+
+// GetFollowing godoc
+// @Summary Get following
+// @Description Get the users the user with given username follows, most recent first. Private accounts only show them to their followers.
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "users per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} UserPage "Get following success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Private Account"
+// @Failure 404 "User Not Found"
+// @Router /users/{username}/following [get]
+func FindFollowing(c *gin.Context) {
+	findFollowUsers(c, "followee_id", "follower_id")
+}
+
+// findFollowUsers lists the users on the listColumn side of the accepted
+// follows whose ownerColumn is the user in the route. The follow lists of
+// private accounts are only shown to their followers.
+func findFollowUsers(c *gin.Context, listColumn, ownerColumn string) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	visible, err := canSeeUser(db, User.ID, userID)
+	if abortUserNotFound(c, err) {
+		return
+	}
+	if !visible {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "this account is private",
+		})
+		return
+	}
+
+	respondFollowUsers(c, db.Where("follows."+ownerColumn+" = ? AND follows.status = ?", User.ID, models.FollowAccepted), listColumn)
+}
+
+// GetFollowRequests godoc
+// @Summary Get follow requests
+// @Description Get the users waiting for the user to approve their follow request, most recent first
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "users per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} UserPage "Get follow requests success"
+// @Failure 401 "Unauthorized"
+// @Router /users/me/follow-requests [get]
+func FindFollowRequest(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	respondFollowUsers(c, db.Where("follows.followee_id = ? AND follows.status = ?", userID, models.FollowPending), "follower_id")
+}
+
+// respondFollowUsers responds with a page of the users in listColumn of the
+// follows matched by query.
+func respondFollowUsers(c *gin.Context, query *gorm.DB, listColumn string) {
+	User := []models.UserSummary{}
+	page := helpers.GetPage(c)
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	err := query.Model(&models.Follow{}).Count(&total).Error
+	if err == nil {
+		err = query.Debug().Model(&models.User{}).Select("users.id", "users.username").
+			Joins("JOIN follows ON follows." + listColumn + " = users.id").
+			Order("follows.id DESC").Scopes(page.Paginate).Find(&User).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, UserPage{
+		Data:  User,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// ApproveFollowRequest godoc
+// @Summary Approve follow request
+// @Description Let the user with given username follow the user
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username of the requester"
+// @Security BearerAuth
+// @Success 200 {string} string "Approve follow request success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Follow Request Not Found"
+// @Router /users/me/follow-requests/{username}/approve [post]
+func ApproveFollowRequest(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		found, err := acceptFollow(tx.Debug(), User.ID, userID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			return gorm.ErrRecordNotFound
+		}
+
+		Follow := models.Follow{FollowerID: User.ID, FolloweeID: userID, Status: models.FollowAccepted}
+		return outbox.Record(tx, events.Event{Type: events.FollowRequestApproved, ActorID: userID, Payload: Follow})
+	})
+	if abortFollowRequestNotFound(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Follow request approved",
+	})
+}
+
+// RejectFollowRequest godoc
+// @Summary Reject follow request
+// @Description Turn down the follow request of the user with given username
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param username path string true "username of the requester"
+// @Security BearerAuth
+// @Success 200 {string} string "Reject follow request success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Follow Request Not Found"
+// @Router /users/me/follow-requests/{username} [delete]
+func RejectFollowRequest(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	User, err := findUserByUsername(db, c.Param("username"))
+	if abortUserNotFound(c, err) {
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		Follow := models.Follow{}
+		result := tx.Debug().Clauses(clause.Returning{}).Where("follower_id = ? AND followee_id = ? AND status = ?", User.ID, userID, models.FollowPending).Delete(&Follow)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.FollowRequestRejected, ActorID: userID, Payload: Follow})
+	})
+	if abortFollowRequestNotFound(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Follow request rejected",
+	})
+}
+
+// UpdatePrivacy godoc
+// @Summary Update privacy
+// @Description Make the user's account private or public. Only approved followers can see the photos, social media and follow lists of private accounts. Making an account public approves all pending follow requests.
+// @Tags follow
+// @Accept json
+// @Produce json
+// @Param is_private query bool true "whether the account is private"
+// @Security BearerAuth
+// @Success 200 {object} Profile "Update privacy success"
+// @Failure 401 "Unauthorized"
+// @Router /users/me/privacy [put]
+func UpdatePrivacy(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := UpdatePrivacyReq{}
+	User := models.User{}
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		tx = tx.Debug()
+		err := tx.Model(&models.User{}).Where("id = ?", userID).UpdateColumn("is_private", req.IsPrivate).Error
+		if err != nil || req.IsPrivate {
+			return err
+		}
+
+		return acceptPendingFollows(tx, userID)
+	})
+	if err == nil {
+		err = db.First(&User, userID).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Profile{
+		ID:             User.ID,
+		Username:       User.Username,
+		IsPrivate:      User.IsPrivate,
+		FollowerCount:  User.FollowerCount,
+		FollowingCount: User.FollowingCount,
+	})
+}
+
+// abortUserNotFound responds to a failed user lookup. It reports whether the
+// request was aborted.
+func abortUserNotFound(c *gin.Context, err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "user doesn't exist",
+		})
+		return true
+	}
+
+	if err != nil {
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return true
+	}
+
+	return false
+}
+
+// abortFollowRequestNotFound responds to a failed follow request update. It
+// reports whether the request was aborted.
+func abortFollowRequestNotFound(c *gin.Context, err error) bool {
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "there is no follow request from this user",
+		})
+		return true
+	}
+
+	return abortUserNotFound(c, err)
+}
@@ -0,0 +1,122 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/events"
+	"tesjwt.go/feed"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+// followedUsersSQL selects the IDs of the users someone follows.
+const followedUsersSQL = "SELECT followee_id FROM follows WHERE follower_id = ? AND status = 'accepted'"
+
+// findUserByUsername loads the user with the given username, ignoring case.
+func findUserByUsername(db *gorm.DB, username string) (models.User, error) {
+	user := models.User{}
+	err := db.Where("LOWER(username) = LOWER(?)", username).Order("id").First(&user).Error
+	return user, err
+}
+
+// isFollowing reports whether the follower's follow of the followee has been
+// accepted.
+func isFollowing(db *gorm.DB, followerID, followeeID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.Follow{}).Where("follower_id = ? AND followee_id = ? AND status = ?", followerID, followeeID, models.FollowAccepted).Count(&count).Error
+	return count > 0, err
+}
+
+// addFollow stores a follow. Accepted follows count towards the follower and
+// following counts and fill the follower's feed right away. Following
+// someone twice changes nothing. It reports whether the follow is new.
+func addFollow(tx *gorm.DB, follow models.Follow) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&follow)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+	if follow.Status != models.FollowAccepted {
+		return true, nil
+	}
+
+	err := updateFollowCounts(tx, follow.FollowerID, follow.FolloweeID, 1)
+	if err != nil {
+		return true, err
+	}
+
+	return true, feed.Backfill(tx, follow.FollowerID, follow.FolloweeID)
+}
+
+// acceptFollow approves a pending follow. It reports whether there was one.
+func acceptFollow(tx *gorm.DB, followerID, followeeID uint) (bool, error) {
+	result := tx.Model(&models.Follow{}).
+		Where("follower_id = ? AND followee_id = ? AND status = ?", followerID, followeeID, models.FollowPending).
+		UpdateColumn("status", models.FollowAccepted)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	err := updateFollowCounts(tx, followerID, followeeID, 1)
+	if err != nil {
+		return true, err
+	}
+
+	return true, feed.Backfill(tx, followerID, followeeID)
+}
+
+// acceptPendingFollows approves every follow request sent to the user.
+func acceptPendingFollows(tx *gorm.DB, followeeID uint) error {
+	followerIDs := []uint{}
+	err := tx.Model(&models.Follow{}).Where("followee_id = ? AND status = ?", followeeID, models.FollowPending).Pluck("follower_id", &followerIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, followerID := range followerIDs {
+		found, err := acceptFollow(tx, followerID, followeeID)
+		if err != nil {
+			return err
+		}
+		if !found {
+			continue
+		}
+
+		follow := models.Follow{FollowerID: followerID, FolloweeID: followeeID, Status: models.FollowAccepted}
+		err = outbox.Record(tx, events.Event{Type: events.FollowRequestApproved, ActorID: followeeID, Payload: follow})
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// removeFollow deletes a follow or a pending follow request. It reports
+// whether there was one.
+func removeFollow(tx *gorm.DB, followerID, followeeID uint) (bool, error) {
+	deleted := []models.Follow{}
+	err := tx.Clauses(clause.Returning{}).Where("follower_id = ? AND followee_id = ?", followerID, followeeID).Delete(&deleted).Error
+	if err != nil || len(deleted) == 0 {
+		return false, err
+	}
+
+	if deleted[0].Status != models.FollowAccepted {
+		return true, nil
+	}
+
+	err = updateFollowCounts(tx, followerID, followeeID, -1)
+	if err != nil {
+		return true, err
+	}
+
+	return true, feed.Remove(tx, followerID, followeeID)
+}
+
+func updateFollowCounts(tx *gorm.DB, followerID, followeeID uint, delta int) error {
+	err := tx.Model(&models.User{}).Where("id = ?", followerID).UpdateColumn("following_count", gorm.Expr("following_count + ?", delta)).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.User{}).Where("id = ?", followeeID).UpdateColumn("follower_count", gorm.Expr("follower_count + ?", delta)).Error
+}
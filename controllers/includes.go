@@ -0,0 +1,49 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+)
+
+// maxEmbeddedComments is how many comments a photo embeds at most. The rest
+// are paged through with FindCommentByPhoto.
+const maxEmbeddedComments = 20
+
+// photoIncludes are the relations a photo can embed for the viewer. Users
+// only carry their public columns, and comments are limited to the latest
+// ones the viewer may see.
+func photoIncludes(viewerID uint) map[string]helpers.Include {
+	return map[string]helpers.Include{
+		"user":          {Preload: "User", Scope: models.PublicUsers},
+		"comments":      {Preload: "Comments", Scope: latestComments(viewerID)},
+		"comments.user": {Preload: "Comments.User", Scope: models.PublicUsers},
+	}
+}
+
+// latestComments limits the comments preloaded for photos to the latest
+// maxEmbeddedComments of each that the viewer may see, oldest first.
+func latestComments(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		ranked := db.Session(&gorm.Session{NewDB: true}).Model(&models.Comment{}).
+			Scopes(visibleComments(viewerID)).
+			Select("comments.id", "comments.photo_id", "ROW_NUMBER() OVER (PARTITION BY comments.photo_id ORDER BY comments.id DESC) AS position")
+
+		return db.Where("comments.id IN (SELECT ranked.id FROM (?) AS ranked WHERE ranked.photo_id = comments.photo_id AND ranked.position <= ?)", ranked, maxEmbeddedComments).
+			Order("comments.id")
+	}
+}
+
+// commentIncludes are the relations a comment can embed for the viewer.
+func commentIncludes(viewerID uint) map[string]helpers.Include {
+	return map[string]helpers.Include{
+		"user":       {Preload: "User", Scope: models.PublicUsers},
+		"photo":      {Preload: "Photo", Scope: viewablePhotos(viewerID)},
+		"photo.user": {Preload: "Photo.User", Scope: models.PublicUsers},
+	}
+}
+
+// socialMediaIncludes are the relations a social media can embed.
+var socialMediaIncludes = map[string]helpers.Include{
+	"user": {Preload: "User", Scope: models.PublicUsers},
+}
@@ -0,0 +1,167 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type UserPage struct {
+	Data []models.UserSummary `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+// LikePhoto godoc
+// @Summary Like photo
+// @Description Like the photo identified by given id. Liking a photo twice changes nothing.
+// @Tags like
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Security BearerAuth
+// @Success 200 {object} interface{} "Like count of the photo"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/like [post]
+func LikePhoto(c *gin.Context) {
+	updateLike(c, true)
+}
+
+// UnlikePhoto godoc
+// @Summary Unlike photo
+// @Description Take back the like of the photo identified by given id
+// @Tags like
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Security BearerAuth
+// @Success 200 {object} interface{} "Like count of the photo"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/like [delete]
+func UnlikePhoto(c *gin.Context) {
+	updateLike(c, false)
+}
+
+// updateLike likes or unlikes the photo in the route for the user and
+// responds with its new like count.
+func updateLike(c *gin.Context, liked bool) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Photo := models.Photo{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	err := db.Scopes(viewablePhotos(userID)).Select("photos.id").First(&Photo, PhotoID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+
+	like := models.Like{
+		UserID:  userID,
+		PhotoID: Photo.ID,
+	}
+
+	if err == nil {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			eventType, update := events.PhotoLiked, addLike
+			if !liked {
+				eventType, update = events.PhotoUnliked, removeLike
+			}
+
+			changed, err := update(tx.Debug(), like)
+			if err != nil || !changed {
+				return err
+			}
+
+			return outbox.Record(tx, events.Event{Type: eventType, ActorID: userID, Payload: like})
+		})
+	}
+	if err == nil {
+		err = db.Select("id", "like_count").First(&Photo, Photo.ID).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"like_count":  Photo.LikeCount,
+		"liked_by_me": liked,
+	})
+}
+
+// GetPhotoLikes godoc
+// @Summary Get photo likes
+// @Description Get the users who liked the photo identified by given id, most recent first
+// @Tags like
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "users per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} UserPage "Get photo likes success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/likes [get]
+func FindPhotoLike(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	User := []models.UserSummary{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	err := db.Scopes(viewablePhotos(userID)).Select("photos.id").First(&models.Photo{}, PhotoID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+
+	var total int64
+	if err == nil {
+		err = db.Model(&models.Like{}).Where("photo_id = ?", PhotoID).Count(&total).Error
+	}
+	if err == nil {
+		err = db.Debug().Model(&models.User{}).Select("users.id", "users.username").
+			Joins("JOIN likes ON likes.user_id = users.id").
+			Where("likes.photo_id = ?", PhotoID).
+			Order("likes.id DESC").Scopes(page.Paginate).Find(&User).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, UserPage{
+		Data:  User,
+		Page:  page,
+		Total: total,
+	})
+}
@@ -0,0 +1,59 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/models"
+)
+
+// attachLikedByMe marks the photos the viewer has liked.
+func attachLikedByMe(db *gorm.DB, viewerID uint, photos []models.Photo) error {
+	if len(photos) == 0 {
+		return nil
+	}
+
+	photoIDs := make([]uint, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	likedIDs := []uint{}
+	err := db.Model(&models.Like{}).Where("user_id = ? AND photo_id IN ?", viewerID, photoIDs).Pluck("photo_id", &likedIDs).Error
+	if err != nil {
+		return err
+	}
+
+	liked := map[uint]bool{}
+	for _, id := range likedIDs {
+		liked[id] = true
+	}
+
+	for i := range photos {
+		photos[i].LikedByMe = liked[photos[i].ID]
+	}
+
+	return nil
+}
+
+// addLike stores a like and bumps the like count of the photo. Liking a
+// photo twice changes nothing. It reports whether the like is new.
+func addLike(tx *gorm.DB, like models.Like) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&like)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	return true, tx.Model(&models.Photo{}).Where("id = ?", like.PhotoID).UpdateColumn("like_count", gorm.Expr("like_count + 1")).Error
+}
+
+// removeLike deletes a like and lowers the like count of the photo.
+// Unliking a photo that was not liked changes nothing. It reports whether
+// there was a like.
+func removeLike(tx *gorm.DB, like models.Like) (bool, error) {
+	result := tx.Where("user_id = ? AND photo_id = ?", like.UserID, like.PhotoID).Delete(&models.Like{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	return true, tx.Model(&models.Photo{}).Where("id = ?", like.PhotoID).UpdateColumn("like_count", gorm.Expr("like_count - 1")).Error
+}
@@ -0,0 +1,78 @@
+package controllers
+
+import (
+	"strings"
+
+	"gorm.io/gorm"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+)
+
+// resolveMentions turns the @username references in text written by the
+// author into mention records. References to unknown users and to users who
+// blocked the author are left as plain text.
+func resolveMentions(db *gorm.DB, authorID uint, text string) ([]models.Mention, error) {
+	tokens := helpers.ParseMentions(text)
+	if len(tokens) == 0 {
+		return nil, nil
+	}
+
+	names := make([]string, 0, len(tokens))
+	for _, token := range tokens {
+		names = append(names, strings.ToLower(token.Username))
+	}
+
+	users := []models.User{}
+	err := db.Select("id", "username").Where("LOWER(username) IN ?", names).
+		Where("id NOT IN (?)", gorm.Expr(blockersSQL, authorID)).Order("id").Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	userIDs := map[string]uint{}
+	for _, user := range users {
+		name := strings.ToLower(user.Username)
+		if _, ok := userIDs[name]; !ok {
+			userIDs[name] = user.ID
+		}
+	}
+
+	mentions := []models.Mention{}
+	for _, token := range tokens {
+		userID, ok := userIDs[strings.ToLower(token.Username)]
+		if !ok {
+			continue
+		}
+
+		mentions = append(mentions, models.Mention{
+			UserID:   userID,
+			Username: token.Username,
+			Offset:   token.Offset,
+			Length:   token.Length,
+		})
+	}
+
+	return mentions, nil
+}
+
+// replaceMentions swaps the stored mentions of a photo or comment for the ones
+// found in its new text.
+func replaceMentions(tx *gorm.DB, targetType string, targetID, authorID uint, text string) ([]models.Mention, error) {
+	err := tx.Unscoped().Where("target_type = ? AND target_id = ?", targetType, targetID).Delete(&models.Mention{}).Error
+	if err != nil {
+		return nil, err
+	}
+
+	mentions, err := resolveMentions(tx, authorID, text)
+	if err != nil || len(mentions) == 0 {
+		return mentions, err
+	}
+
+	for i := range mentions {
+		mentions[i].TargetType = targetType
+		mentions[i].TargetID = targetID
+	}
+
+	err = tx.Create(&mentions).Error
+	return mentions, err
+}
@@ -0,0 +1,89 @@
+package controllers
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+var errNotPhotoOwner = errors.New("only the owner of the photo can moderate its comments")
+
+// findOwnedPhoto loads a photo the user can see, failing with
+// errNotPhotoOwner when it belongs to someone else.
+func findOwnedPhoto(db *gorm.DB, photoID, userID uint) (models.Photo, error) {
+	photo := models.Photo{}
+	err := db.Scopes(viewablePhotos(userID)).Select("photos.id", "photos.user_id").First(&photo, photoID).Error
+	if err == nil && photo.UserID != userID {
+		err = errNotPhotoOwner
+	}
+
+	return photo, err
+}
+
+// findCommentSettings loads the comment settings of a photo. Photos whose
+// owner never changed them can be commented on by everyone.
+func findCommentSettings(db *gorm.DB, photoID uint) (models.CommentSettings, error) {
+	settings := models.CommentSettings{
+		PhotoID:   photoID,
+		Policy:    models.CommentsEveryone,
+		Blocklist: []string{},
+	}
+
+	err := db.Where("photo_id = ?", photoID).Limit(1).Find(&settings).Error
+	return settings, err
+}
+
+// commentRestriction explains why the user may not comment on the photo. It
+// returns an empty string when they may. Owners can always comment on their
+// own photos.
+func commentRestriction(db *gorm.DB, photo models.Photo, settings models.CommentSettings, userID uint) (string, error) {
+	if photo.UserID == userID {
+		return "", nil
+	}
+
+	switch settings.Policy {
+	case models.CommentsDisabled:
+		return "comments are turned off for this photo", nil
+	case models.CommentsFollowers:
+		following, err := isFollowing(db, userID, photo.UserID)
+		if err != nil || following {
+			return "", err
+		}
+		return "only followers of the owner can comment on this photo", nil
+	}
+
+	return "", nil
+}
+
+// visibleComments limits a comment query to the comments the viewer may
+// see: visible ones, their own, and all comments on their photos, as long as
+// the viewer may open the photo they are on.
+func visibleComments(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(comments.status = ? OR comments.user_id = ? OR comments.photo_id IN (SELECT id FROM photos WHERE photos.user_id = ?))", models.CommentVisible, viewerID, viewerID).
+			Scopes(onViewablePhotos(viewerID))
+	}
+}
+
+// pruneHiddenComments drops the comments of a photo the viewer may not see
+// together with the replies under them. Comments have to be ordered by ID so
+// that parents come before their replies.
+func pruneHiddenComments(comments []models.Comment, photoOwnerID, viewerID uint) []models.Comment {
+	if photoOwnerID == viewerID {
+		return comments
+	}
+
+	pruned := map[uint]bool{}
+	visible := []models.Comment{}
+	for _, comment := range comments {
+		hidden := comment.Status != models.CommentVisible && comment.UserID != viewerID
+		if hidden || (comment.ParentID != nil && pruned[*comment.ParentID]) {
+			pruned[comment.ID] = true
+			continue
+		}
+		visible = append(visible, comment)
+	}
+
+	return visible
+}
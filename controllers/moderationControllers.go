@@ -0,0 +1,263 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type UpdateCommentStatusReq struct {
+	Status string `json:"status" form:"status" valid:"required~Status is required,in(visible|hidden)~Status must be visible or hidden"`
+}
+
+// GetCommentSettings godoc
+// @Summary Get comment settings
+// @Description Get who can comment on the photo identified by given id and the keywords that hold comments for review. Only the owner of the photo can see them.
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Security BearerAuth
+// @Success 200 {object} models.CommentSettings "Get comment settings success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/comment-settings [get]
+func FindCommentSettings(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	_, err := findOwnedPhoto(db, uint(PhotoID), userID)
+	if abortPhotoModeration(c, err) {
+		return
+	}
+
+	CommentSettings, err := findCommentSettings(db, uint(PhotoID))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommentSettings)
+}
+
+// UpdateCommentSettings godoc
+// @Summary Update comment settings
+// @Description Set who can comment on the photo identified by given id (everyone, followers or disabled) and the keywords that hold comments for review
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param settings body models.CommentSettings true "Comment settings"
+// @Security BearerAuth
+// @Success 200 {object} models.CommentSettings "Update comment settings success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/comment-settings [put]
+func UpdateCommentSettings(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	CommentSettings := models.CommentSettings{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	_, err := findOwnedPhoto(db, uint(PhotoID), userID)
+	if abortPhotoModeration(c, err) {
+		return
+	}
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&CommentSettings)
+	} else {
+		c.ShouldBind(&CommentSettings)
+	}
+
+	CommentSettings.PhotoID = uint(PhotoID)
+	if CommentSettings.Policy == "" {
+		CommentSettings.Policy = models.CommentsEveryone
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Clauses(clause.OnConflict{UpdateAll: true}).Create(&CommentSettings).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.CommentSettingsUpdated, ActorID: userID, Payload: CommentSettings})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, CommentSettings)
+}
+
+// GetHeldComments godoc
+// @Summary Get held comments
+// @Description Get the comments on the photo identified by given id that matched its blocklist and wait for review, oldest first
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Security BearerAuth
+// @Success 200 {object} []models.Comment "Get held comments success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/comments/held [get]
+func FindHeldComment(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Comment := []models.Comment{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	_, err := findOwnedPhoto(db, uint(PhotoID), userID)
+	if abortPhotoModeration(c, err) {
+		return
+	}
+
+	err = db.Debug().Preload("Mentions").Where("photo_id = ? AND status = ?", PhotoID, models.CommentHeld).Order("id").Find(&Comment).Error
+	if err == nil {
+		err = attachCommentAuthors(db, Comment)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Comment)
+}
+
+// UpdateCommentStatus godoc
+// @Summary Moderate comment
+// @Description Hide a comment on one of the user's photos, or make a hidden or held comment visible again
+// @Tags moderation
+// @Accept json
+// @Produce json
+// @Param commentID path int true "ID of the comment"
+// @Param status query string true "visible or hidden"
+// @Security BearerAuth
+// @Success 200 {object} models.Comment "Moderate comment success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Comment Not Found"
+// @Router /comment/{commentID}/status [put]
+func UpdateCommentStatus(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := UpdateCommentStatusReq{}
+	Comment := models.Comment{}
+
+	CommentID, _ := strconv.Atoi(c.Param("commentID"))
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	_, err := govalidator.ValidateStruct(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Preload("Mentions").First(&Comment, CommentID).Error
+		if err != nil {
+			return err
+		}
+
+		_, err = findOwnedPhoto(tx, Comment.PhotoID, userID)
+		if err != nil {
+			return err
+		}
+
+		if Comment.Status == req.Status {
+			return nil
+		}
+
+		held := Comment.Status == models.CommentHeld
+		Comment.Status = req.Status
+		err = tx.Debug().Model(&Comment).UpdateColumn("status", Comment.Status).Error
+		if err != nil {
+			return err
+		}
+
+		err = outbox.Record(tx, events.Event{Type: events.CommentModerated, ActorID: userID, Payload: Comment})
+		if err != nil || !held || Comment.Status != models.CommentVisible {
+			return err
+		}
+
+		// Held comments aren't announced when they are posted, so they are
+		// once the owner approves them, on behalf of their author.
+		return outbox.Record(tx, events.Event{Type: events.CommentCreated, ActorID: Comment.UserID, Payload: Comment})
+	})
+	if abortPhotoModeration(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, Comment)
+}
+
+// abortPhotoModeration responds to a failed ownership check of a photo. It
+// reports whether the request was aborted.
+func abortPhotoModeration(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "data doesn't exist",
+		})
+	case errors.Is(err, errNotPhotoOwner):
+		c.AbortWithStatusJSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+	}
+
+	return true
+}
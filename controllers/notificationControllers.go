@@ -0,0 +1,236 @@
+package controllers
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+)
+
+type NotificationPage struct {
+	Data []models.Notification `json:"data"`
+	helpers.Page
+	Total  int64 `json:"total"`
+	Unread int64 `json:"unread"`
+}
+
+type ReadNotificationReq struct {
+	IDs []uint `json:"ids" form:"ids"`
+}
+
+// GetNotifications godoc
+// @Summary Get notifications
+// @Description Get the notifications of the user, newest first, with the number of unread ones
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param unread query bool false "only list unread notifications"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "notifications per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} NotificationPage "Get notifications success"
+// @Failure 401 "Unauthorized"
+// @Router /notifications [get]
+func FindNotification(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Notification := []models.Notification{}
+
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	base := db.Model(&models.Notification{}).Where("notifications.user_id = ?", userID).Scopes(viewableNotifications(userID)).Session(&gorm.Session{})
+	unread := base.Where("notifications.read_at IS NULL").Session(&gorm.Session{})
+
+	query := base
+	if c.Query("unread") == "true" {
+		query = unread
+	}
+
+	NotificationPage := NotificationPage{Page: page}
+	err := query.Count(&NotificationPage.Total).Error
+	if err == nil {
+		err = unread.Count(&NotificationPage.Unread).Error
+	}
+	if err == nil {
+		err = query.Debug().Order("notifications.id DESC").Scopes(page.Paginate).Find(&Notification).Error
+	}
+	if err == nil {
+		err = attachNotificationActors(db, Notification)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	NotificationPage.Data = Notification
+	c.JSON(http.StatusOK, NotificationPage)
+}
+
+// ReadNotifications godoc
+// @Summary Mark notifications read
+// @Description Mark the notifications with given ids as read, or all of the user's notifications when no ids are given
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param ids body ReadNotificationReq false "IDs of the notifications"
+// @Security BearerAuth
+// @Success 200 {object} interface{} "Number of notifications marked read"
+// @Failure 401 "Unauthorized"
+// @Router /notifications/read [post]
+func ReadNotification(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := ReadNotificationReq{}
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	query := db.Debug().Model(&models.Notification{}).Where("user_id = ? AND read_at IS NULL", userID)
+	if len(req.IDs) > 0 {
+		query = query.Where("id IN ?", req.IDs)
+	}
+
+	result := query.UpdateColumn("read_at", time.Now())
+	if result.Error != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": result.Error.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"read": result.RowsAffected,
+	})
+}
+
+// GetNotificationPreferences godoc
+// @Summary Get notification preferences
+// @Description Get which types of notifications the user gets: comment, reply, like, follow, follow_request and mention
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} map[string]bool "Get notification preferences success"
+// @Failure 401 "Unauthorized"
+// @Router /notifications/preferences [get]
+func FindNotificationPreference(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	preferences, err := findNotificationPreferences(db, userID)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// UpdateNotificationPreferences godoc
+// @Summary Update notification preferences
+// @Description Turn types of notifications on or off. Types left out keep their current setting.
+// @Tags notification
+// @Accept json
+// @Produce json
+// @Param preferences body map[string]bool true "Whether to get each type of notification"
+// @Security BearerAuth
+// @Success 200 {object} map[string]bool "Update notification preferences success"
+// @Failure 400 "Unknown Notification Type"
+// @Failure 401 "Unauthorized"
+// @Router /notifications/preferences [put]
+func UpdateNotificationPreference(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	req := map[string]bool{}
+	userID := uint(userData["id"].(float64))
+
+	err := c.ShouldBindJSON(&req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	NotificationPreference := []models.NotificationPreference{}
+	for _, notificationType := range models.NotificationTypes {
+		enabled, ok := req[notificationType]
+		if !ok {
+			continue
+		}
+		delete(req, notificationType)
+
+		NotificationPreference = append(NotificationPreference, models.NotificationPreference{
+			UserID:  userID,
+			Type:    notificationType,
+			Enabled: enabled,
+		})
+	}
+
+	for notificationType := range req {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "unknown notification type " + notificationType,
+		})
+		return
+	}
+
+	if len(NotificationPreference) > 0 {
+		err = db.Debug().Clauses(clause.OnConflict{UpdateAll: true}).Create(&NotificationPreference).Error
+	}
+
+	var preferences map[string]bool
+	if err == nil {
+		preferences, err = findNotificationPreferences(db, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, preferences)
+}
+
+// findNotificationPreferences tells for every type of notification whether
+// the user gets it.
+func findNotificationPreferences(db *gorm.DB, userID uint) (map[string]bool, error) {
+	stored := []models.NotificationPreference{}
+	err := db.Where("user_id = ?", userID).Find(&stored).Error
+	if err != nil {
+		return nil, err
+	}
+
+	preferences := map[string]bool{}
+	for _, notificationType := range models.NotificationTypes {
+		preferences[notificationType] = true
+	}
+	for _, preference := range stored {
+		preferences[preference.Type] = preference.Enabled
+	}
+
+	return preferences, nil
+}
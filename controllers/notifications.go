@@ -0,0 +1,40 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// viewableNotifications limits a notification query to the ones about
+// photos and comments the viewer may still see. Notifications about
+// something that was deleted or hidden since are left out rather than
+// removed, so they come back if it does.
+func viewableNotifications(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		newDB := db.Session(&gorm.Session{NewDB: true})
+		photoIDs := newDB.Model(&models.Photo{}).Select("photos.id").Scopes(viewablePhotos(viewerID))
+		commentIDs := newDB.Model(&models.Comment{}).Select("comments.id").Scopes(visibleComments(viewerID))
+
+		return db.Where("(notifications.photo_id IS NULL OR notifications.photo_id IN (?))", photoIDs).
+			Where("(notifications.comment_id IS NULL OR notifications.comment_id IN (?))", commentIDs)
+	}
+}
+
+// attachNotificationActors fills in the user behind every notification.
+func attachNotificationActors(db *gorm.DB, notifications []models.Notification) error {
+	userIDs := make([]uint, 0, len(notifications))
+	for _, notification := range notifications {
+		userIDs = append(userIDs, notification.ActorID)
+	}
+
+	actors, err := findUserSummaries(db, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range notifications {
+		notifications[i].Actor = actors[notifications[i].ActorID]
+	}
+
+	return nil
+}
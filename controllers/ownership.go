@@ -0,0 +1,25 @@
+package controllers
+
+import (
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+var errNotOwner = errors.New("only the author can change or delete it")
+
+// missedOwnedRow explains why a write limited to the rows of the user
+// didn't touch the row with the given id: gorm.ErrRecordNotFound when there
+// is no such row, errNotOwner when it belongs to someone else.
+func missedOwnedRow(tx *gorm.DB, model interface{}, id uint) error {
+	var count int64
+	err := tx.Model(model).Where("id = ?", id).Count(&count).Error
+	if err != nil {
+		return err
+	}
+	if count == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	return errNotOwner
+}
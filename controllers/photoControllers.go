@@ -1,16 +1,44 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"time"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/feed"
 	"tesjwt.go/helpers"
 	"tesjwt.go/models"
+	"tesjwt.go/outbox"
 )
 
+type PhotoCursorPage struct {
+	Data       []models.Photo `json:"data"`
+	NextCursor string         `json:"next_cursor,omitempty"`
+}
+
+// photoList is what the list of photos can be filtered and sorted by.
+var photoList = helpers.ListSpec{
+	Fields: map[string]helpers.ListField{
+		"id":           {Column: "photos.id", Type: helpers.FieldNumber, Sort: true},
+		"user_id":      {Column: "photos.user_id", Type: helpers.FieldNumber, Filter: true},
+		"title":        {Column: "photos.title", Type: helpers.FieldString, Filter: true, Sort: true},
+		"visibility":   {Column: "photos.visibility", Type: helpers.FieldString, Filter: true},
+		"like_count":   {Column: "photos.like_count", Type: helpers.FieldNumber, Sort: true},
+		"created_at":   {Column: "photos.created_at", Type: helpers.FieldTime, Filter: true, Sort: true},
+		"published_at": {Column: "photos.published_at", Type: helpers.FieldTime, Filter: true, Sort: true},
+	},
+	Search:      []string{"photos.title", "photos.caption"},
+	DefaultSort: "-id",
+	ID:          "photos.id",
+}
+
 // CreatePhoto godoc
 // @Summary Create photo
 // @Description Create photo to post in mygram
@@ -20,6 +48,9 @@ import (
 // @Param title query string true "title"
 // @Param caption query string false "caption"
 // @Param photo_url query string true "photo_url"
+// @Param visibility query string false "public, followers, private or unlisted"
+// @Param status query string false "draft or published, defaults to published"
+// @Param publish_at query string false "RFC 3339 time to publish the photo at"
 // @Security BearerAuth
 // @Success 201 {object} models.Photo "Create photo success"
 // @Failure 401 "Unauthorized"
@@ -39,8 +70,58 @@ func CreatePhoto(c *gin.Context) {
 	}
 
 	Photo.UserID = userID
+	Photo.User = nil
+	Photo.Comments = nil
+	Photo.Reactions = nil
+	Photo.LikeCount = 0
+	if Photo.Visibility == "" {
+		Photo.Visibility = models.VisibilityPublic
+	}
+
+	err := applyPublishState(&Photo, time.Now())
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
 
-	err := db.Debug().Create(&Photo).Error
+	mentions, err := resolveMentions(db, userID, Photo.Caption)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{
+			"error":   "Internal Server Error",
+			"message": err.Error(),
+		})
+		return
+	}
+	Photo.Mentions = mentions
+
+	err = db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&Photo).Error
+		if err != nil {
+			return err
+		}
+
+		err = feed.FanOut(tx, Photo)
+		if err != nil {
+			return err
+		}
+
+		err = outbox.Record(tx, events.Event{Type: events.PhotoCreated, ActorID: userID, Payload: Photo})
+		if err != nil || Photo.Status != models.PhotoPublished {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.PhotoPublished, ActorID: userID, Payload: Photo})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -62,6 +143,7 @@ func CreatePhoto(c *gin.Context) {
 // @Security BearerAuth
 // @Success 200 {object} models.Photo{} "Update photo success"
 // @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
 // @Failure 404 "Photo Not Found"
 // @Router /photo/{photoID} [put]
 func UpdatePhoto(c *gin.Context) {
@@ -81,8 +163,64 @@ func UpdatePhoto(c *gin.Context) {
 
 	Photo.UserID = userID
 	Photo.ID = uint(PhotoID)
+	Photo.User = nil
+	Photo.Comments = nil
+	Photo.Mentions = nil
+	Photo.Reactions = nil
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&Photo).Where("id = ? AND user_id = ?", PhotoID, userID).Updates(models.Photo{Title: Photo.Title, Caption: Photo.Caption, PhotoUrl: Photo.PhotoUrl, Visibility: Photo.Visibility})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missedOwnedRow(tx, &models.Photo{}, uint(PhotoID))
+		}
+
+		var err error
+		var published bool
+		if Photo.Status != "" || Photo.PublishAt != nil {
+			published, err = updatePublishState(tx, &Photo)
+			if err != nil {
+				return err
+			}
+		}
+
+		if Photo.Caption != "" {
+			Photo.Mentions, err = replaceMentions(tx, models.TargetPhoto, Photo.ID, userID, Photo.Caption)
+			if err != nil {
+				return err
+			}
+		}
 
-	err := db.Model(&Photo).Where("id = ?", PhotoID).Updates(models.Photo{Title: Photo.Title, Caption: Photo.Caption, PhotoUrl: Photo.PhotoUrl}).Error
+		// The request only carries the fields that changed, so the photo is
+		// reloaded for the events and the response to have all of them.
+		err = tx.First(&Photo, PhotoID).Error
+		if err != nil {
+			return err
+		}
+
+		err = outbox.Record(tx, events.Event{Type: events.PhotoUpdated, ActorID: userID, Payload: Photo})
+		if err != nil || !published {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.PhotoPublished, ActorID: userID, Payload: Photo})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+	if errors.Is(err, errNotOwner) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -104,6 +242,7 @@ func UpdatePhoto(c *gin.Context) {
 // @Security BearerAuth
 // @Success 200 {string} string "Delete photo success"
 // @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
 // @Failure 404 "Photo Not Found"
 // @Router /photo/{photoID} [delete]
 func DeletePhoto(c *gin.Context) {
@@ -124,7 +263,41 @@ func DeletePhoto(c *gin.Context) {
 	Photo.UserID = userID
 	Photo.ID = uint(PhotoID)
 
-	err := db.Model(&Photo).Where("id = ?", PhotoID).Delete(&Photo).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		// Comments go to the trash together with their photo and share its
+		// deletion time, which is how they are found again on restore.
+		deletedAt := time.Now()
+
+		deleted := models.Photo{}
+		result := tx.Model(&deleted).Clauses(clause.Returning{}).Where("id = ? AND user_id = ?", PhotoID, userID).UpdateColumn("deleted_at", deletedAt)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return missedOwnedRow(tx, &models.Photo{}, uint(PhotoID))
+		}
+
+		err := tx.Model(&models.Comment{}).Where("photo_id = ?", PhotoID).UpdateColumn("deleted_at", deletedAt).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.PhotoDeleted, ActorID: userID, Payload: deleted})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+	if errors.Is(err, errNotOwner) {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": err.Error(),
+		})
+		return
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -140,13 +313,16 @@ func DeletePhoto(c *gin.Context) {
 
 // GetPhoto godoc
 // @Summary Get photo
-// @Description Get photo by ID
+// @Description Get photo by ID, with the relations asked for in include. Embedded users only show their profile, and embedded comments only the latest 20 the user may see.
 // @Tags photo
 // @Accept json
 // @Produce json
 // @Param photoId path int true "ID of the photo"
+// @Param fields query string false "fields of the photo to return, separated by commas"
+// @Param include query string false "user, comments (the latest 20) or comments.user, separated by commas"
 // @Security BearerAuth
 // @Success 200 {object} models.Photo{} "Get photo success"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
 // @Failure 404 "Photo Not Found"
 // @Router /photo/{photoID} [get]
@@ -168,7 +344,7 @@ func FindPhotoById(c *gin.Context) {
 	Photo.UserID = userID
 	Photo.ID = uint(PhotoID)
 
-	err := db.Model(&Photo).Where("id = ?", PhotoID).Find(&Photo).Error
+	sparse, err := helpers.ParseSparse(c, models.Photo{}, photoIncludes(userID))
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -177,24 +353,66 @@ func FindPhotoById(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, Photo)
+	err = db.Model(&Photo).Scopes(viewablePhotos(userID), sparse.Preload).Preload("Mentions").Where("id = ?", PhotoID).First(&Photo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+
+	var reactions map[uint]map[string]int
+	if err == nil {
+		reactions, err = findReactionCounts(db, models.TargetPhoto, []uint{Photo.ID})
+		Photo.Reactions = reactions[Photo.ID]
+	}
+	if err == nil {
+		err = db.Model(&models.Like{}).Select("count(*) > 0").Where("user_id = ? AND photo_id = ?", userID, Photo.ID).Scan(&Photo.LikedByMe).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	sparse.JSON(c, http.StatusOK, Photo)
 }
 
 // GetAllPhotos godoc
 // @Summary Get all photos
-// @Description Get all existing photos
+// @Description Get all photos visible to the user, leaving out the photos of users they muted, newest first. Pass the next_cursor of a page as after to get the page that follows it, which the Link header also points at. Cursors only work with the filters and sort they were made with.
 // @Tags photo
 // @Accept json
 // @Produce json
+// @Param user_id query string false "IDs of the owners, separated by commas"
+// @Param title query string false "exact title"
+// @Param visibility query string false "public, followers or unlisted"
+// @Param created_after query string false "date or RFC 3339 time"
+// @Param created_before query string false "date or RFC 3339 time"
+// @Param published_after query string false "date or RFC 3339 time"
+// @Param published_before query string false "date or RFC 3339 time"
+// @Param q query string false "text to look for in the title and caption"
+// @Param sort query string false "id, title, like_count, created_at or published_at, separated by commas and prefixed with - to sort descending, defaults to -id"
+// @Param fields query string false "fields of the photos to return, separated by commas"
+// @Param include query string false "user, comments (the latest 20 of each photo) or comments.user, separated by commas"
+// @Param after query string false "next_cursor of the previous page"
+// @Param limit query int false "photos per page, at most 100"
 // @Security BearerAuth
-// @Success 200 {object} []models.Photo{} "Get all photos success"
+// @Success 200 {object} PhotoCursorPage "Get all photos success"
+// @Header 200 {string} Link "link to the next page"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
 // @Failure 404 "Photos Not Found"
 // @Router /photo [get]
 func FindAllPhoto(c *gin.Context) {
 	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
 	contentType := helpers.GetContentType(c)
 	Photo := []models.Photo{}
+	userID := uint(userData["id"].(float64))
 
 	if contentType == appJSON {
 		c.ShouldBindJSON(&Photo)
@@ -202,7 +420,60 @@ func FindAllPhoto(c *gin.Context) {
 		c.ShouldBind(&Photo)
 	}
 
-	err := db.Debug().Find(&Photo).Error
+	list, err := helpers.ParseList(c, photoList)
+
+	var sparse helpers.Sparse
+	if err == nil {
+		sparse, err = helpers.ParseSparse(c, models.Photo{}, photoIncludes(userID))
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	keyset := helpers.GetKeyset(c, list.Sort...)
+	next, err := keyset.Find(db.Debug().Scopes(listablePhotos(userID), unmutedPhotos(userID), list.Filter, sparse.Preload).Preload("Mentions"), &Photo)
+	if err == nil {
+		err = attachPhotoReactions(db, Photo)
+	}
+	if err == nil {
+		err = attachLikedByMe(db, userID, Photo)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	helpers.SetNextLink(c, next, keyset.Limit)
+	sparse.JSON(c, http.StatusOK, PhotoCursorPage{
+		Data:       Photo,
+		NextCursor: next,
+	})
+}
+
+// GetUnpublishedPhotos godoc
+// @Summary Get unpublished photos
+// @Description Get the drafts and scheduled photos of the user
+// @Tags photo
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []models.Photo{} "Get unpublished photos success"
+// @Failure 401 "Unauthorized"
+// @Router /photo/drafts [get]
+func FindUnpublishedPhoto(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Photo := []models.Photo{}
+	userID := uint(userData["id"].(float64))
+
+	err := db.Debug().Preload("Mentions").Where("user_id = ? AND status <> ?", userID, models.PhotoPublished).Order("publish_at, id").Find(&Photo).Error
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -0,0 +1,67 @@
+package controllers
+
+import (
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/feed"
+	"tesjwt.go/models"
+)
+
+// applyPublishState works out the publishing state of a photo from the status
+// and publish_at sent by the client. Photos with a publish_at in the future
+// are scheduled, drafts stay hidden until they are published explicitly and
+// everything else is published right away.
+func applyPublishState(photo *models.Photo, now time.Time) error {
+	switch {
+	case photo.Status == models.PhotoDraft:
+		photo.PublishAt = nil
+		photo.PublishedAt = nil
+	case photo.PublishAt != nil && photo.PublishAt.After(now):
+		photo.Status = models.PhotoScheduled
+		photo.PublishedAt = nil
+	case photo.Status == models.PhotoScheduled:
+		return errors.New("publish_at has to be in the future to schedule a photo")
+	default:
+		photo.Status = models.PhotoPublished
+		photo.PublishedAt = &now
+	}
+
+	return nil
+}
+
+// updatePublishState moves a stored photo to the publishing state requested
+// by the client. Photos that are already published keep their original
+// publish time, and photos published just now are fanned out to feeds. It
+// reports whether the photo was published just now.
+func updatePublishState(tx *gorm.DB, photo *models.Photo) (bool, error) {
+	current := models.Photo{}
+	err := tx.Select("id", "user_id", "visibility", "status", "published_at").First(&current, photo.ID).Error
+	if err != nil {
+		return false, err
+	}
+
+	err = applyPublishState(photo, time.Now())
+	if err != nil {
+		return false, err
+	}
+
+	if current.Status == models.PhotoPublished && photo.Status == models.PhotoPublished {
+		photo.PublishedAt = current.PublishedAt
+	}
+
+	err = tx.Model(&models.Photo{}).Where("id = ?", photo.ID).UpdateColumns(map[string]interface{}{
+		"status":       photo.Status,
+		"publish_at":   photo.PublishAt,
+		"published_at": photo.PublishedAt,
+	}).Error
+	if err != nil || current.Status == models.PhotoPublished || photo.Status != models.PhotoPublished {
+		return false, err
+	}
+
+	current.Status = photo.Status
+	current.PublishedAt = photo.PublishedAt
+
+	return true, feed.FanOut(tx, current)
+}
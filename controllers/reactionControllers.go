@@ -0,0 +1,173 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+// AddPhotoReaction godoc
+// @Summary React to photo
+// @Description Add an emoji reaction to the photo identified by given id. Reacting twice with the same emoji changes nothing.
+// @Tags reaction
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param emoji path string true "emoji"
+// @Security BearerAuth
+// @Success 200 {object} map[string]int "Reaction counts of the photo"
+// @Failure 400 "Invalid Emoji"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/reactions/{emoji} [put]
+func AddPhotoReaction(c *gin.Context) {
+	updateReaction(c, models.TargetPhoto, "photoID", true)
+}
+
+// DeletePhotoReaction godoc
+// @Summary Remove photo reaction
+// @Description Remove an emoji reaction from the photo identified by given id
+// @Tags reaction
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param emoji path string true "emoji"
+// @Security BearerAuth
+// @Success 200 {object} map[string]int "Reaction counts of the photo"
+// @Failure 400 "Invalid Emoji"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/reactions/{emoji} [delete]
+func DeletePhotoReaction(c *gin.Context) {
+	updateReaction(c, models.TargetPhoto, "photoID", false)
+}
+
+// AddCommentReaction godoc
+// @Summary React to comment
+// @Description Add an emoji reaction to the comment identified by given id. Reacting twice with the same emoji changes nothing.
+// @Tags reaction
+// @Accept json
+// @Produce json
+// @Param commentID path int true "ID of the comment"
+// @Param emoji path string true "emoji"
+// @Security BearerAuth
+// @Success 200 {object} map[string]int "Reaction counts of the comment"
+// @Failure 400 "Invalid Emoji"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Comment Not Found"
+// @Router /comment/{commentID}/reactions/{emoji} [put]
+func AddCommentReaction(c *gin.Context) {
+	updateReaction(c, models.TargetComment, "commentID", true)
+}
+
+// DeleteCommentReaction godoc
+// @Summary Remove comment reaction
+// @Description Remove an emoji reaction from the comment identified by given id
+// @Tags reaction
+// @Accept json
+// @Produce json
+// @Param commentID path int true "ID of the comment"
+// @Param emoji path string true "emoji"
+// @Security BearerAuth
+// @Success 200 {object} map[string]int "Reaction counts of the comment"
+// @Failure 400 "Invalid Emoji"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Comment Not Found"
+// @Router /comment/{commentID}/reactions/{emoji} [delete]
+func DeleteCommentReaction(c *gin.Context) {
+	updateReaction(c, models.TargetComment, "commentID", false)
+}
+
+// updateReaction adds or removes the reaction of the user to the photo or
+// comment named by targetParam and responds with the new counts.
+func updateReaction(c *gin.Context, targetType, targetParam string, add bool) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+
+	targetID, _ := strconv.Atoi(c.Param(targetParam))
+	userID := uint(userData["id"].(float64))
+	emoji := c.Param("emoji")
+
+	if !helpers.IsEmoji(emoji) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "reactions have to be a single emoji",
+		})
+		return
+	}
+
+	var err error
+	if targetType == models.TargetPhoto {
+		err = db.Scopes(viewablePhotos(userID)).Select("photos.id").First(&models.Photo{}, targetID).Error
+	} else {
+		// Nobody reacts to the comments of users they blocked or who
+		// blocked them.
+		comment := models.Comment{}
+		err = db.Scopes(visibleComments(userID)).Select("comments.id", "comments.user_id").First(&comment, targetID).Error
+		if err == nil {
+			var blocked bool
+			blocked, err = isBlockedEitherWay(db, userID, comment.UserID)
+			if err == nil && blocked {
+				err = gorm.ErrRecordNotFound
+			}
+		}
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "data doesn't exist",
+		})
+		return
+	}
+
+	reaction := models.Reaction{
+		UserID:     userID,
+		TargetType: targetType,
+		TargetID:   uint(targetID),
+		Emoji:      emoji,
+	}
+
+	if err == nil {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			eventType, update := events.ReactionAdded, addReaction
+			if !add {
+				eventType, update = events.ReactionRemoved, removeReaction
+			}
+
+			changed, err := update(tx.Debug(), reaction)
+			if err != nil || !changed {
+				return err
+			}
+
+			return outbox.Record(tx, events.Event{Type: eventType, ActorID: userID, Payload: reaction})
+		})
+	}
+
+	var counts map[uint]map[string]int
+	if err == nil {
+		counts, err = findReactionCounts(db, targetType, []uint{reaction.TargetID})
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	Reactions := counts[reaction.TargetID]
+	if Reactions == nil {
+		Reactions = map[string]int{}
+	}
+
+	c.JSON(http.StatusOK, Reactions)
+}
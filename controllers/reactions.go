@@ -0,0 +1,108 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/models"
+)
+
+// findReactionCounts loads the emoji counts of the given photos or comments,
+// keyed by target ID.
+func findReactionCounts(db *gorm.DB, targetType string, targetIDs []uint) (map[uint]map[string]int, error) {
+	counts := map[uint]map[string]int{}
+	if len(targetIDs) == 0 {
+		return counts, nil
+	}
+
+	rows := []models.ReactionCount{}
+	err := db.Where("target_type = ? AND target_id IN ? AND count > 0", targetType, targetIDs).Find(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, row := range rows {
+		if counts[row.TargetID] == nil {
+			counts[row.TargetID] = map[string]int{}
+		}
+		counts[row.TargetID][row.Emoji] = row.Count
+	}
+
+	return counts, nil
+}
+
+// attachPhotoReactions fills in the emoji counts of every photo.
+func attachPhotoReactions(db *gorm.DB, photos []models.Photo) error {
+	photoIDs := make([]uint, 0, len(photos))
+	for _, photo := range photos {
+		photoIDs = append(photoIDs, photo.ID)
+	}
+
+	counts, err := findReactionCounts(db, models.TargetPhoto, photoIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range photos {
+		photos[i].Reactions = counts[photos[i].ID]
+	}
+
+	return nil
+}
+
+// attachCommentReactions fills in the emoji counts of every comment.
+func attachCommentReactions(db *gorm.DB, comments []models.Comment) error {
+	commentIDs := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		commentIDs = append(commentIDs, comment.ID)
+	}
+
+	counts, err := findReactionCounts(db, models.TargetComment, commentIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].Reactions = counts[comments[i].ID]
+	}
+
+	return nil
+}
+
+// addReaction stores a reaction and bumps its count. Adding a reaction the
+// user already made changes nothing. It reports whether the reaction is new.
+func addReaction(tx *gorm.DB, reaction models.Reaction) (bool, error) {
+	result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&reaction)
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	count := models.ReactionCount{
+		TargetType: reaction.TargetType,
+		TargetID:   reaction.TargetID,
+		Emoji:      reaction.Emoji,
+		Count:      1,
+	}
+
+	return true, tx.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "target_type"}, {Name: "target_id"}, {Name: "emoji"}},
+		DoUpdates: clause.Assignments(map[string]interface{}{"count": gorm.Expr("reaction_counts.count + 1")}),
+	}).Create(&count).Error
+}
+
+// removeReaction deletes a reaction and lowers its count. Removing a reaction
+// the user never made changes nothing. It reports whether there was a
+// reaction.
+func removeReaction(tx *gorm.DB, reaction models.Reaction) (bool, error) {
+	result := tx.Where("user_id = ? AND target_type = ? AND target_id = ? AND emoji = ?", reaction.UserID, reaction.TargetType, reaction.TargetID, reaction.Emoji).Delete(&models.Reaction{})
+	if result.Error != nil || result.RowsAffected == 0 {
+		return false, result.Error
+	}
+
+	count := tx.Model(&models.ReactionCount{}).Where("target_type = ? AND target_id = ? AND emoji = ?", reaction.TargetType, reaction.TargetID, reaction.Emoji).Session(&gorm.Session{})
+	err := count.UpdateColumn("count", gorm.Expr("count - 1")).Error
+	if err != nil {
+		return true, err
+	}
+
+	return true, count.Where("count <= 0").Delete(&models.ReactionCount{}).Error
+}
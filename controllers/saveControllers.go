@@ -0,0 +1,243 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type SavePhotoReq struct {
+	Collection string `json:"collection" form:"collection" valid:"maxstringlength(50)~Collection can't be longer than 50 characters"`
+}
+
+type PhotoPage struct {
+	Data []models.Photo `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+// SavePhoto godoc
+// @Summary Save photo
+// @Description Save the photo identified by given id for later, optionally into a named collection. Saving a saved photo again moves it to the given collection. Nobody else can see what the user saved.
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Param collection query string false "name of the collection, at most 50 characters"
+// @Security BearerAuth
+// @Success 200 {object} models.SavedPhoto "Save photo success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Photo Not Found"
+// @Router /photo/{photoID}/save [post]
+func SavePhoto(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := SavePhotoReq{}
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	req.Collection = strings.TrimSpace(req.Collection)
+	_, err := govalidator.ValidateStruct(req)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	err = db.Scopes(viewablePhotos(userID)).Select("photos.id").First(&models.Photo{}, PhotoID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo doesn't exist",
+		})
+		return
+	}
+
+	SavedPhoto := models.SavedPhoto{
+		UserID:     userID,
+		PhotoID:    uint(PhotoID),
+		Collection: req.Collection,
+	}
+
+	if err == nil {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			err := tx.Debug().Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "user_id"}, {Name: "photo_id"}},
+				DoUpdates: clause.AssignmentColumns([]string{"collection"}),
+			}).Create(&SavedPhoto).Error
+			if err != nil {
+				return err
+			}
+
+			return outbox.Record(tx, events.Event{Type: events.PhotoSaved, ActorID: userID, Payload: SavedPhoto})
+		})
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SavedPhoto)
+}
+
+// UnsavePhoto godoc
+// @Summary Unsave photo
+// @Description Remove the photo identified by given id from the user's saved photos
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Param photoID path int true "ID of the photo"
+// @Security BearerAuth
+// @Success 200 {string} string "Unsave photo success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Saved Photo Not Found"
+// @Router /photo/{photoID}/save [delete]
+func UnsavePhoto(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+
+	PhotoID, _ := strconv.Atoi(c.Param("photoID"))
+	userID := uint(userData["id"].(float64))
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		SavedPhoto := models.SavedPhoto{}
+		result := tx.Debug().Clauses(clause.Returning{}).Where("user_id = ? AND photo_id = ?", userID, PhotoID).Delete(&SavedPhoto)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.PhotoUnsaved, ActorID: userID, Payload: SavedPhoto})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "photo isn't saved",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Photo unsaved",
+	})
+}
+
+// GetSavedPhotos godoc
+// @Summary Get saved photos
+// @Description Get the photos the user saved, most recently saved first. Photos that were deleted or that the user can no longer see are left out.
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Param collection query string false "only list the photos in this collection"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "photos per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} PhotoPage "Get saved photos success"
+// @Failure 401 "Unauthorized"
+// @Router /saved [get]
+func FindSavedPhoto(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Photo := []models.Photo{}
+
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	query := db.Model(&models.Photo{}).Joins("JOIN saved_photos ON saved_photos.photo_id = photos.id AND saved_photos.user_id = ?", userID).
+		Scopes(viewablePhotos(userID))
+	if collection, ok := c.GetQuery("collection"); ok {
+		query = query.Where("saved_photos.collection = ?", strings.TrimSpace(collection))
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err == nil {
+		err = query.Debug().Preload("Mentions").Order("saved_photos.id DESC").Scopes(page.Paginate).Find(&Photo).Error
+	}
+	if err == nil {
+		err = attachPhotoReactions(db, Photo)
+	}
+	if err == nil {
+		err = attachLikedByMe(db, userID, Photo)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, PhotoPage{
+		Data:  Photo,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// GetSavedCollections godoc
+// @Summary Get saved collections
+// @Description Get the names of the user's collections of saved photos with the number of photos in each, by name. Photos saved without a collection are counted under an empty name.
+// @Tags saved
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []models.SavedCollection "Get saved collections success"
+// @Failure 401 "Unauthorized"
+// @Router /saved/collections [get]
+func FindSavedCollection(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	SavedCollection := []models.SavedCollection{}
+	userID := uint(userData["id"].(float64))
+
+	err := db.Debug().Model(&models.Photo{}).Select("saved_photos.collection AS name", "COUNT(*) AS count").
+		Joins("JOIN saved_photos ON saved_photos.photo_id = photos.id AND saved_photos.user_id = ?", userID).
+		Scopes(viewablePhotos(userID)).Group("saved_photos.collection").Order("saved_photos.collection").
+		Scan(&SavedCollection).Error
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, SavedCollection)
+}
@@ -1,16 +1,39 @@
 package controllers
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
 
 	"github.com/dgrijalva/jwt-go"
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"tesjwt.go/database"
+	"tesjwt.go/events"
 	"tesjwt.go/helpers"
 	"tesjwt.go/models"
+	"tesjwt.go/outbox"
 )
 
+type SocialMediaCursorPage struct {
+	Data       []models.SocialMedia `json:"data"`
+	NextCursor string               `json:"next_cursor,omitempty"`
+}
+
+// socialMediaList is what the list of social media can be filtered and
+// sorted by.
+var socialMediaList = helpers.ListSpec{
+	Fields: map[string]helpers.ListField{
+		"id":         {Column: "social_media.id", Type: helpers.FieldNumber, Sort: true},
+		"user_id":    {Column: "social_media.user_id", Type: helpers.FieldNumber, Filter: true},
+		"name":       {Column: "social_media.name", Type: helpers.FieldString, Filter: true, Sort: true},
+		"created_at": {Column: "social_media.created_at", Type: helpers.FieldTime, Filter: true, Sort: true},
+	},
+	Search:      []string{"social_media.name", "social_media.social_media_url"},
+	DefaultSort: "-id",
+	ID:          "social_media.id",
+}
+
 // CreateSocialMedia godoc
 // @Summary Create social media
 // @Description Create social media of the user
@@ -39,7 +62,14 @@ func CreateSocialMedia(c *gin.Context) {
 
 	SocialMedia.UserID = userID
 
-	err := db.Debug().Create(&SocialMedia).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&SocialMedia).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.SocialMediaCreated, ActorID: userID, Payload: SocialMedia})
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -81,7 +111,14 @@ func UpdateSocialMedia(c *gin.Context) {
 	SocialMedia.UserID = userID
 	SocialMedia.ID = uint(socialmediaID)
 
-	err := db.Model(&SocialMedia).Where("id = ?", socialmediaID).Updates(models.SocialMedia{Name: SocialMedia.Name, SocialMediaUrl: SocialMedia.SocialMediaUrl}).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&SocialMedia).Where("id = ?", socialmediaID).Updates(models.SocialMedia{Name: SocialMedia.Name, SocialMediaUrl: SocialMedia.SocialMediaUrl}).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.SocialMediaUpdated, ActorID: userID, Payload: SocialMedia})
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -123,7 +160,14 @@ func DeleteSocialMedia(c *gin.Context) {
 	SocialMedia.UserID = userID
 	SocialMedia.ID = uint(socialmediaID)
 
-	err := db.Model(&SocialMedia).Where("id = ?", socialmediaID).Delete(&SocialMedia).Error
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&SocialMedia).Where("id = ?", socialmediaID).Delete(&SocialMedia).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.SocialMediaDeleted, ActorID: userID, Payload: SocialMedia})
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -139,13 +183,16 @@ func DeleteSocialMedia(c *gin.Context) {
 
 // GetSocialMedia godoc
 // @Summary Get social media
-// @Description Get social media identified by given id
+// @Description Get social media identified by given id, with its owner when include is user. The owner only shows their profile.
 // @Tags social media
 // @Accept json
 // @Produce json
 // @Param socialMediaId path int true "ID of the social media"
+// @Param fields query string false "fields of the social media to return, separated by commas"
+// @Param include query string false "user"
 // @Security BearerAuth
 // @Success 200 {object} models.SocialMedia "Get social media success"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
 // @Failure 404 "Social Media Not Found"
 // @Router /socialmedia/{socialmediaID} [get]
@@ -167,7 +214,7 @@ func FindSocialMediaById(c *gin.Context) {
 	SocialMedia.UserID = userID
 	SocialMedia.ID = uint(socialmediaID)
 
-	err := db.Model(&SocialMedia).Where("id = ?", socialmediaID).Find(&SocialMedia).Error
+	sparse, err := helpers.ParseSparse(c, models.SocialMedia{}, socialMediaIncludes)
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -176,24 +223,54 @@ func FindSocialMediaById(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, SocialMedia)
+	err = db.Model(&SocialMedia).Scopes(visibleOwners("social_media.user_id", userID), sparse.Preload).Where("id = ?", socialmediaID).First(&SocialMedia).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "social media doesn't exist",
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	sparse.JSON(c, http.StatusOK, SocialMedia)
 }
 
 // GetAllSocialMedia godoc
 // @Summary Get all social media
-// @Description Get all social media in mygram
+// @Description Get the social media of every user whose account the user may see, newest first. Pass the next_cursor of a page as after to get the page that follows it, which the Link header also points at. Cursors only work with the filters and sort they were made with.
 // @Tags social media
 // @Accept json
 // @Produce json
+// @Param user_id query string false "IDs of the owners, separated by commas"
+// @Param name query string false "exact name"
+// @Param created_after query string false "date or RFC 3339 time"
+// @Param created_before query string false "date or RFC 3339 time"
+// @Param q query string false "text to look for in the name and URL"
+// @Param sort query string false "id, name or created_at, separated by commas and prefixed with - to sort descending, defaults to -id"
+// @Param fields query string false "fields of the social media to return, separated by commas"
+// @Param include query string false "user"
+// @Param after query string false "next_cursor of the previous page"
+// @Param limit query int false "social media per page, at most 100"
 // @Security BearerAuth
-// @Success 200 {object} []models.SocialMedia "Get all social media success"
+// @Success 200 {object} SocialMediaCursorPage "Get all social media success"
+// @Header 200 {string} Link "link to the next page"
+// @Failure 400 "Bad Request"
 // @Failure 401 "Unauthorized"
 // @Failure 404 "Social Media Not Found"
 // @Router /socialmedia [get]
 func FindAllSocialMedia(c *gin.Context) {
 	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
 	contentType := helpers.GetContentType(c)
 	SocialMedia := []models.SocialMedia{}
+	userID := uint(userData["id"].(float64))
 
 	if contentType == appJSON {
 		c.ShouldBindJSON(&SocialMedia)
@@ -201,7 +278,12 @@ func FindAllSocialMedia(c *gin.Context) {
 		c.ShouldBind(&SocialMedia)
 	}
 
-	err := db.Debug().Find(&SocialMedia).Error
+	list, err := helpers.ParseList(c, socialMediaList)
+
+	var sparse helpers.Sparse
+	if err == nil {
+		sparse, err = helpers.ParseSparse(c, models.SocialMedia{}, socialMediaIncludes)
+	}
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -210,5 +292,19 @@ func FindAllSocialMedia(c *gin.Context) {
 		return
 	}
 
-	c.JSON(http.StatusOK, SocialMedia)
+	keyset := helpers.GetKeyset(c, list.Sort...)
+	next, err := keyset.Find(db.Debug().Scopes(visibleOwners("social_media.user_id", userID), list.Filter, sparse.Preload), &SocialMedia)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	helpers.SetNextLink(c, next, keyset.Limit)
+	sparse.JSON(c, http.StatusOK, SocialMediaCursorPage{
+		Data:       SocialMedia,
+		NextCursor: next,
+	})
 }
@@ -0,0 +1,91 @@
+package controllers
+
+import (
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// activeStories limits a story query to the stories that haven't expired
+// yet of users the viewer may see.
+func activeStories(viewerID uint, now time.Time) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("stories.archived_at IS NULL AND stories.expires_at > ?", now).
+			Scopes(visibleOwners("stories.user_id", viewerID))
+	}
+}
+
+// attachStoryDetails fills in the author of every story and whether the
+// viewer has watched it.
+func attachStoryDetails(db *gorm.DB, stories []models.Story, viewerID uint) error {
+	if len(stories) == 0 {
+		return nil
+	}
+
+	storyIDs := make([]uint, 0, len(stories))
+	userIDs := make([]uint, 0, len(stories))
+	for _, story := range stories {
+		storyIDs = append(storyIDs, story.ID)
+		userIDs = append(userIDs, story.UserID)
+	}
+
+	authors, err := findUserSummaries(db, userIDs)
+	if err != nil {
+		return err
+	}
+
+	viewedIDs := []uint{}
+	err = db.Model(&models.StoryView{}).Where("viewer_id = ? AND story_id IN ?", viewerID, storyIDs).Pluck("story_id", &viewedIDs).Error
+	if err != nil {
+		return err
+	}
+
+	viewed := map[uint]bool{}
+	for _, id := range viewedIDs {
+		viewed[id] = true
+	}
+
+	for i := range stories {
+		stories[i].Author = authors[stories[i].UserID]
+		stories[i].ViewedByMe = viewed[stories[i].ID] || stories[i].UserID == viewerID
+	}
+
+	return nil
+}
+
+// groupStories groups stories, which have to be ordered oldest first, by
+// their author. The viewer's own stories come first, then the users with
+// stories the viewer hasn't watched yet, each by their latest story.
+func groupStories(stories []models.Story, viewerID uint) []models.StoryGroup {
+	groups := []models.StoryGroup{}
+	position := map[uint]int{}
+	for _, story := range stories {
+		i, ok := position[story.UserID]
+		if !ok {
+			i = len(groups)
+			position[story.UserID] = i
+			groups = append(groups, models.StoryGroup{User: story.Author})
+		}
+
+		groups[i].Stories = append(groups[i].Stories, story)
+		groups[i].HasUnseen = groups[i].HasUnseen || !story.ViewedByMe
+		if story.CreatedAt != nil {
+			groups[i].LastPosted = *story.CreatedAt
+		}
+	}
+
+	sort.SliceStable(groups, func(i, j int) bool {
+		iOwn, jOwn := groups[i].Stories[0].UserID == viewerID, groups[j].Stories[0].UserID == viewerID
+		if iOwn != jOwn {
+			return iOwn
+		}
+		if groups[i].HasUnseen != groups[j].HasUnseen {
+			return groups[i].HasUnseen
+		}
+		return groups[i].LastPosted.After(groups[j].LastPosted)
+	})
+
+	return groups
+}
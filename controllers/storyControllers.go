@@ -0,0 +1,282 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type StoryPage struct {
+	Data []models.Story `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+// CreateStory godoc
+// @Summary Create story
+// @Description Share a story for 24 hours. Stories follow the privacy of the account, so private accounts only share them with followers.
+// @Tags story
+// @Accept json
+// @Produce json
+// @Param media_url query string true "media_url"
+// @Param caption query string false "caption"
+// @Security BearerAuth
+// @Success 201 {object} models.Story "Create story success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Router /stories [post]
+func CreateStory(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	Story := models.Story{}
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&Story)
+	} else {
+		c.ShouldBind(&Story)
+	}
+
+	now := time.Now()
+	Story = models.Story{
+		CreatedAt: &now,
+		UserID:    userID,
+		MediaUrl:  Story.MediaUrl,
+		Caption:   Story.Caption,
+		ExpiresAt: now.Add(models.StoryLifetime),
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&Story).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.StoryCreated, ActorID: userID, Payload: Story})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Story)
+}
+
+// GetStoryFeed godoc
+// @Summary Get story feed
+// @Description Get the active stories of the user and of the users they follow and haven't muted, grouped by user. The user's own stories come first, then the users with stories they haven't watched yet.
+// @Tags story
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []models.StoryGroup "Get story feed success"
+// @Failure 401 "Unauthorized"
+// @Router /stories/feed [get]
+func FindStoryFeed(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Story := []models.Story{}
+	userID := uint(userData["id"].(float64))
+
+	err := db.Debug().Scopes(activeStories(userID, time.Now())).
+		Where("(stories.user_id = ? OR stories.user_id IN (?))", userID, gorm.Expr(followedUsersSQL, userID)).
+		Where("stories.user_id NOT IN (?)", gorm.Expr(mutedUsersSQL, userID)).
+		Order("stories.id").Find(&Story).Error
+	if err == nil {
+		err = attachStoryDetails(db, Story, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, groupStories(Story, userID))
+}
+
+// GetStoryArchive godoc
+// @Summary Get story archive
+// @Description Get the user's expired stories, most recent first. Only the owner can see archived stories.
+// @Tags story
+// @Accept json
+// @Produce json
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "stories per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} StoryPage "Get story archive success"
+// @Failure 401 "Unauthorized"
+// @Router /stories/archive [get]
+func FindStoryArchive(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Story := []models.Story{}
+
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	query := db.Model(&models.Story{}).Where("user_id = ? AND archived_at IS NOT NULL", userID).Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err == nil {
+		err = query.Debug().Order("id DESC").Scopes(page.Paginate).Find(&Story).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, StoryPage{
+		Data:  Story,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// GetStory godoc
+// @Summary Get story
+// @Description Watch the story identified by given id. Watching someone else's story adds the user to its viewers.
+// @Tags story
+// @Accept json
+// @Produce json
+// @Param storyID path int true "ID of the story"
+// @Security BearerAuth
+// @Success 200 {object} models.Story "Get story success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Story Not Found"
+// @Router /stories/{storyID} [get]
+func FindStoryById(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Story := models.Story{}
+
+	StoryID, _ := strconv.Atoi(c.Param("storyID"))
+	userID := uint(userData["id"].(float64))
+
+	// Owners can still open their archived stories.
+	err := db.First(&Story, StoryID).Error
+	if err == nil && Story.UserID != userID {
+		err = db.Scopes(activeStories(userID, time.Now())).Select("stories.id").Where("stories.id = ?", Story.ID).First(&models.Story{}).Error
+	}
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "story doesn't exist",
+		})
+		return
+	}
+
+	if err == nil && Story.UserID != userID {
+		err = db.Transaction(func(tx *gorm.DB) error {
+			result := tx.Debug().Clauses(clause.OnConflict{DoNothing: true}).Create(&models.StoryView{StoryID: Story.ID, ViewerID: userID})
+			if result.Error != nil || result.RowsAffected == 0 {
+				return result.Error
+			}
+
+			Story.ViewCount++
+			return tx.Model(&models.Story{}).Where("id = ?", Story.ID).UpdateColumn("view_count", gorm.Expr("view_count + 1")).Error
+		})
+	}
+
+	Stories := []models.Story{Story}
+	if err == nil {
+		err = attachStoryDetails(db, Stories, userID)
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	Stories[0].ViewedByMe = true
+	c.JSON(http.StatusOK, Stories[0])
+}
+
+// GetStoryViewers godoc
+// @Summary Get story viewers
+// @Description Get the users who watched the story identified by given id, most recent first. Only the owner of the story can see them.
+// @Tags story
+// @Accept json
+// @Produce json
+// @Param storyID path int true "ID of the story"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "users per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} UserPage "Get story viewers success"
+// @Failure 401 "Unauthorized"
+// @Failure 403 "Forbidden"
+// @Failure 404 "Story Not Found"
+// @Router /stories/{storyID}/viewers [get]
+func FindStoryViewer(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Story := models.Story{}
+	User := []models.UserSummary{}
+
+	StoryID, _ := strconv.Atoi(c.Param("storyID"))
+	userID := uint(userData["id"].(float64))
+	page := helpers.GetPage(c)
+
+	err := db.Select("id", "user_id").First(&Story, StoryID).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "story doesn't exist",
+		})
+		return
+	}
+
+	if err == nil && Story.UserID != userID {
+		c.JSON(http.StatusForbidden, gin.H{
+			"error":   "Forbidden",
+			"message": "only the owner of the story can see who watched it",
+		})
+		return
+	}
+
+	var total int64
+	if err == nil {
+		err = db.Model(&models.StoryView{}).Where("story_id = ?", Story.ID).Count(&total).Error
+	}
+	if err == nil {
+		err = db.Debug().Model(&models.User{}).Select("users.id", "users.username").
+			Joins("JOIN story_views ON story_views.viewer_id = users.id").
+			Where("story_views.story_id = ?", Story.ID).
+			Order("story_views.created_at DESC").Scopes(page.Paginate).Find(&User).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, UserPage{
+		Data:  User,
+		Page:  page,
+		Total: total,
+	})
+}
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"tesjwt.go/database"
+	"tesjwt.go/models"
+	"tesjwt.go/stream"
+)
+
+// Stream godoc
+// @Summary Stream updates
+// @Description Push new notifications, photos for the feed, direct messages and comments on the watched photos as they happen, instead of polling for them. Requests that ask for a WebSocket upgrade get one, everything else gets Server-Sent Events. Every message has an id. Reconnecting with the last one as Last-Event-ID, or as last_event_id, sends what was missed in between, or a resync message when that is no longer known. Clients that can't set headers can pass their token as access_token.
+// @Tags stream
+// @Produce text/event-stream
+// @Param watch query string false "comma-separated IDs of the photos to get new comments on"
+// @Param last_event_id query int false "ID of the last message received"
+// @Param access_token query string false "JWT, for clients that can't send the Authorization header"
+// @Security BearerAuth
+// @Success 200 {object} stream.Message "Stream of messages"
+// @Failure 401 "Unauthorized"
+// @Router /stream [get]
+func Stream(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	// Comments are only streamed for the photos the user may open.
+	watched := []uint{}
+	if watch := c.Query("watch"); watch != "" {
+		photoIDs := []uint{}
+		for _, id := range strings.Split(watch, ",") {
+			photoID, err := strconv.Atoi(strings.TrimSpace(id))
+			if err == nil && photoID > 0 {
+				photoIDs = append(photoIDs, uint(photoID))
+			}
+		}
+
+		if len(photoIDs) > 0 {
+			db.Model(&models.Photo{}).Scopes(viewablePhotos(userID)).Where("photos.id IN ?", photoIDs).Pluck("photos.id", &watched)
+		}
+	}
+
+	lastEventID := c.GetHeader("Last-Event-ID")
+	if lastEventID == "" {
+		lastEventID = c.Query("last_event_id")
+	}
+	resumeFrom, _ := strconv.ParseUint(lastEventID, 10, 64)
+
+	client := streamHub.Subscribe(userID, watched, resumeFrom)
+	defer streamHub.Unsubscribe(client)
+
+	if strings.EqualFold(c.GetHeader("Upgrade"), "websocket") {
+		stream.ServeWebSocket(c.Writer, c.Request, client)
+		return
+	}
+
+	stream.ServeSSE(c.Writer, c.Request, client)
+}
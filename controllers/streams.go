@@ -0,0 +1,103 @@
+package controllers
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+	"tesjwt.go/events"
+	"tesjwt.go/models"
+	"tesjwt.go/stream"
+)
+
+// streamHub passes what happens in the app on to the open streams.
+var streamHub = stream.NewHub()
+
+// RegisterStreamEvents pushes new notifications, comments, feed photos and
+// direct messages to the open streams that should see them.
+func RegisterStreamEvents(db *gorm.DB) {
+	events.Subscribe(events.NotificationCreated, func(event events.Event) {
+		notification := event.Payload.(models.Notification)
+
+		// Notifications the list would hide aren't pushed either.
+		var viewable int64
+		err := db.Model(&models.Notification{}).Scopes(viewableNotifications(notification.UserID)).Where("notifications.id = ?", notification.ID).Count(&viewable).Error
+		if err != nil {
+			log.Println("error streaming notification :", err)
+			return
+		}
+		if viewable == 0 {
+			return
+		}
+
+		notifications := []models.Notification{notification}
+		err = attachNotificationActors(db, notifications)
+		if err != nil {
+			log.Println("error streaming notification :", err)
+			return
+		}
+
+		streamHub.SendToUser(notification.UserID, stream.TypeNotification, notifications[0])
+	})
+
+	events.Subscribe(events.CommentCreated, func(event events.Event) {
+		comment := event.Payload.(models.Comment)
+		if comment.Status == models.CommentHeld {
+			return
+		}
+
+		comments := []models.Comment{comment}
+		err := attachCommentAuthors(db, comments)
+		if err != nil {
+			log.Println("error streaming comment :", err)
+			return
+		}
+
+		streamHub.SendToWatchers(comment.PhotoID, stream.TypeComment, comments[0])
+	})
+
+	events.Subscribe(events.MessageCreated, func(event events.Event) {
+		message := event.Payload.(models.Message)
+		// Members that can't see a shared photo themselves only get its ID.
+		message.Photo = nil
+
+		userIDs := []uint{}
+		err := db.Model(&models.ConversationMember{}).Where("conversation_id = ?", message.ConversationID).Pluck("user_id", &userIDs).Error
+		if err != nil {
+			log.Println("error streaming message :", err)
+			return
+		}
+
+		for _, userID := range userIDs {
+			streamHub.SendToUser(userID, stream.TypeMessage, message)
+		}
+	})
+
+	events.Subscribe(events.PhotoPublished, func(event events.Event) {
+		photo := event.Payload.(models.Photo)
+		userIDs, err := feedRecipients(db, photo, streamHub.Connected())
+		if err != nil {
+			log.Println("error streaming feed photo :", err)
+			return
+		}
+
+		for _, userID := range userIDs {
+			streamHub.SendToUser(userID, stream.TypeFeed, photo)
+		}
+	})
+}
+
+// feedRecipients picks the users, out of the given ones, whose feed the
+// newly published photo goes into: followers of its owner who didn't mute
+// them, as long as the photo is shared with followers.
+func feedRecipients(db *gorm.DB, photo models.Photo, userIDs []uint) ([]uint, error) {
+	if len(userIDs) == 0 || (photo.Visibility != models.VisibilityPublic && photo.Visibility != models.VisibilityFollowers) {
+		return nil, nil
+	}
+
+	recipients := []uint{}
+	err := db.Model(&models.Follow{}).
+		Where("followee_id = ? AND status = ? AND follower_id IN ?", photo.UserID, models.FollowAccepted, userIDs).
+		Where("follower_id NOT IN (SELECT muter_id FROM mutes WHERE muted_id = ?)", photo.UserID).
+		Pluck("follower_id", &recipients).Error
+	return recipients, err
+}
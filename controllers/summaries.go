@@ -0,0 +1,45 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// findUserSummaries loads the summaries of the given users keyed by ID.
+func findUserSummaries(db *gorm.DB, userIDs []uint) (map[uint]*models.UserSummary, error) {
+	summaries := map[uint]*models.UserSummary{}
+	if len(userIDs) == 0 {
+		return summaries, nil
+	}
+
+	users := []models.UserSummary{}
+	err := db.Model(&models.User{}).Select("id", "username").Where("id IN ?", userIDs).Find(&users).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range users {
+		summaries[users[i].ID] = &users[i]
+	}
+
+	return summaries, nil
+}
+
+// attachCommentAuthors fills in the author of every comment.
+func attachCommentAuthors(db *gorm.DB, comments []models.Comment) error {
+	userIDs := make([]uint, 0, len(comments))
+	for _, comment := range comments {
+		userIDs = append(userIDs, comment.UserID)
+	}
+
+	authors, err := findUserSummaries(db, userIDs)
+	if err != nil {
+		return err
+	}
+
+	for i := range comments {
+		comments[i].Author = authors[comments[i].UserID]
+	}
+
+	return nil
+}
@@ -0,0 +1,120 @@
+package controllers
+
+import (
+	"sort"
+
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// commentThreadSQL selects the IDs of the given comments and of all their
+// replies, however deeply they are nested.
+const commentThreadSQL = `WITH RECURSIVE thread AS (
+	SELECT id FROM comments WHERE id IN ?
+	UNION ALL
+	SELECT comments.id FROM comments JOIN thread ON comments.parent_id = thread.id
+) SELECT id FROM thread`
+
+// inCommentThreads limits a comment query to the given comments and their
+// replies.
+func inCommentThreads(commentIDs ...uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("comments.id IN (?)", gorm.Expr(commentThreadSQL, commentIDs))
+	}
+}
+
+// commentOrders are the orderings of top-level comments a client can ask
+// for. Top comments are the ones with the most visible direct replies, so
+// held and hidden replies don't give away moderation.
+var commentOrders = map[string]string{
+	"oldest": "comments.id",
+	"newest": "comments.id DESC",
+	"top":    "(SELECT COUNT(*) FROM comments AS replies WHERE replies.parent_id = comments.id AND replies.status = 'visible' AND replies.deleted_at IS NULL) DESC, comments.id",
+}
+
+// commentChildren groups comments by parent. Comments whose parent is not in
+// the list are returned as roots.
+func commentChildren(comments []models.Comment) (roots []int, children map[uint][]int) {
+	children = map[uint][]int{}
+	known := map[uint]bool{}
+	for _, comment := range comments {
+		known[comment.ID] = true
+	}
+
+	for i, comment := range comments {
+		if comment.ParentID != nil && known[*comment.ParentID] {
+			children[*comment.ParentID] = append(children[*comment.ParentID], i)
+		} else {
+			roots = append(roots, i)
+		}
+	}
+
+	return roots, children
+}
+
+// nestComments arranges comments into reply trees. Every comment carries the
+// number of direct replies it has.
+func nestComments(comments []models.Comment) []models.Comment {
+	roots, children := commentChildren(comments)
+
+	var build func(i int) models.Comment
+	build = func(i int) models.Comment {
+		comment := comments[i]
+		comment.Replies = nil
+		for _, child := range children[comment.ID] {
+			comment.Replies = append(comment.Replies, build(child))
+		}
+		comment.ReplyCount = len(children[comment.ID])
+		return comment
+	}
+
+	threads := []models.Comment{}
+	for _, root := range roots {
+		threads = append(threads, build(root))
+	}
+
+	return threads
+}
+
+// flattenComments returns the top-level comments with every reply in their
+// thread listed oldest first directly under them. Top-level comments carry
+// the size of their whole thread, replies the number of direct replies.
+func flattenComments(comments []models.Comment) []models.Comment {
+	roots, children := commentChildren(comments)
+
+	var collect func(parentID uint, replies []models.Comment) []models.Comment
+	collect = func(parentID uint, replies []models.Comment) []models.Comment {
+		for _, child := range children[parentID] {
+			reply := comments[child]
+			reply.Replies = nil
+			reply.ReplyCount = len(children[reply.ID])
+			replies = collect(reply.ID, append(replies, reply))
+		}
+		return replies
+	}
+
+	threads := []models.Comment{}
+	for _, root := range roots {
+		thread := comments[root]
+		thread.Replies = collect(thread.ID, nil)
+		sort.SliceStable(thread.Replies, func(i, j int) bool {
+			return thread.Replies[i].ID < thread.Replies[j].ID
+		})
+		thread.ReplyCount = len(thread.Replies)
+		threads = append(threads, thread)
+	}
+
+	return threads
+}
+
+// sortThreads puts threads in the order of their top-level comment IDs.
+func sortThreads(threads []models.Comment, threadIDs []uint) {
+	position := map[uint]int{}
+	for i, id := range threadIDs {
+		position[id] = i
+	}
+
+	sort.SliceStable(threads, func(i, j int) bool {
+		return position[threads[i].ID] < position[threads[j].ID]
+	})
+}
@@ -0,0 +1,218 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+type TrashItem struct {
+	Type      string      `json:"type"`
+	ID        uint        `json:"id"`
+	DeletedAt time.Time   `json:"deleted_at"`
+	PurgeAt   time.Time   `json:"purge_at"`
+	Data      interface{} `json:"data"`
+}
+
+// trashModels maps the :type segment of the trash routes to its model.
+var trashModels = map[string]func() interface{}{
+	"photo":       func() interface{} { return &models.Photo{} },
+	"comment":     func() interface{} { return &models.Comment{} },
+	"socialmedia": func() interface{} { return &models.SocialMedia{} },
+}
+
+// restoreEvents are the events recorded when an item of each trash type is
+// restored.
+var restoreEvents = map[string]string{
+	"photo":       events.PhotoRestored,
+	"comment":     events.CommentRestored,
+	"socialmedia": events.SocialMediaRestored,
+}
+
+// GetTrash godoc
+// @Summary Get trash
+// @Description Get the deleted photos, comments and social media of the user that can still be restored
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []controllers.TrashItem "Get trash success"
+// @Failure 401 "Unauthorized"
+// @Router /trash [get]
+func FindTrash(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+	retention := helpers.TrashRetention()
+
+	Photo := []models.Photo{}
+	Comment := []models.Comment{}
+	SocialMedia := []models.SocialMedia{}
+
+	deleted := db.Debug().Unscoped().Where("user_id = ? AND deleted_at IS NOT NULL", userID).Session(&gorm.Session{})
+	err := deleted.Find(&Photo).Error
+	if err == nil {
+		err = deleted.Find(&Comment).Error
+	}
+	if err == nil {
+		err = deleted.Find(&SocialMedia).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	Trash := []TrashItem{}
+	add := func(itemType string, model models.GormModel, data interface{}) {
+		Trash = append(Trash, TrashItem{
+			Type:      itemType,
+			ID:        model.ID,
+			DeletedAt: model.DeletedAt.Time,
+			PurgeAt:   model.DeletedAt.Time.Add(retention),
+			Data:      data,
+		})
+	}
+	for i := range Photo {
+		add("photo", Photo[i].GormModel, Photo[i])
+	}
+	for i := range Comment {
+		add("comment", Comment[i].GormModel, Comment[i])
+	}
+	for i := range SocialMedia {
+		add("socialmedia", SocialMedia[i].GormModel, SocialMedia[i])
+	}
+
+	sort.Slice(Trash, func(i, j int) bool {
+		return Trash[i].DeletedAt.After(Trash[j].DeletedAt)
+	})
+
+	c.JSON(http.StatusOK, Trash)
+}
+
+// RestoreTrash godoc
+// @Summary Restore from trash
+// @Description Restore a deleted photo, comment or social media of the user
+// @Tags trash
+// @Accept json
+// @Produce json
+// @Param type path string true "photo, comment or socialmedia"
+// @Param id path int true "ID of the deleted item"
+// @Security BearerAuth
+// @Success 200 {string} string "Restore success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Item Not Found"
+// @Failure 409 "Photo Or Parent Comment Is Deleted"
+// @Router /trash/{type}/{id}/restore [post]
+func RestoreTrash(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	userID := uint(userData["id"].(float64))
+
+	itemID, _ := strconv.Atoi(c.Param("id"))
+	itemType := c.Param("type")
+	if _, ok := trashModels[itemType]; !ok {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "unknown trash type",
+		})
+		return
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := restoreItem(tx.Debug(), itemType, uint(itemID), userID)
+		if err != nil {
+			return err
+		}
+
+		restored := trashModels[itemType]()
+		err = tx.First(restored, itemID).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: restoreEvents[itemType], ActorID: userID, Payload: restored})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "item is not in the trash",
+		})
+		return
+	}
+	if errors.Is(err, errParentDeleted) {
+		c.JSON(http.StatusConflict, gin.H{
+			"error":   "Conflict",
+			"message": err.Error(),
+		})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Item restored",
+	})
+}
+
+var errParentDeleted = errors.New("the photo or comment this comment belongs to is deleted, restore it first")
+
+// restoreItem takes an item of the user out of the trash. Restoring a photo
+// or a comment also restores the comments and replies that were deleted along
+// with it, while a comment can only come back once its photo and the comment
+// it replies to are back.
+func restoreItem(tx *gorm.DB, itemType string, itemID, userID uint) error {
+	model := trashModels[itemType]()
+	trashed := models.GormModel{}
+	err := tx.Unscoped().Model(model).Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", itemID, userID).First(&trashed).Error
+	if err != nil {
+		return err
+	}
+
+	switch itemType {
+	case "photo":
+		err = tx.Unscoped().Model(&models.Comment{}).
+			Where("photo_id = ? AND deleted_at = ?", itemID, trashed.DeletedAt).
+			UpdateColumn("deleted_at", nil).Error
+	case "comment":
+		comment := models.Comment{}
+		err = tx.Unscoped().Select("photo_id", "parent_id").First(&comment, itemID).Error
+		if err == nil {
+			err = tx.Select("id").First(&models.Photo{}, comment.PhotoID).Error
+		}
+		if err == nil && comment.ParentID != nil {
+			err = tx.Select("id").First(&models.Comment{}, *comment.ParentID).Error
+		}
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			err = errParentDeleted
+		}
+		if err == nil {
+			err = tx.Unscoped().Model(&models.Comment{}).Scopes(inCommentThreads(itemID)).
+				Where("deleted_at = ?", trashed.DeletedAt).
+				UpdateColumn("deleted_at", nil).Error
+		}
+	}
+	if err != nil {
+		return err
+	}
+
+	return tx.Unscoped().Model(model).Where("id = ?", itemID).UpdateColumn("deleted_at", nil).Error
+}
@@ -4,9 +4,12 @@ import (
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
 	"tesjwt.go/database"
+	"tesjwt.go/events"
 	"tesjwt.go/helpers"
 	"tesjwt.go/models"
+	"tesjwt.go/outbox"
 )
 
 var appJSON = "application/json"
@@ -21,6 +24,7 @@ var appJSON = "application/json"
 // @Param email query string true "email"
 // @Param password query string true "password"
 // @Param age query int true "age"
+// @Param is_private query bool false "only approved followers can see the photos of private accounts"
 // @Success 201 {object} models.User "Register success response"
 // @Router /users/register [post]
 func UserRegister(c *gin.Context) {
@@ -35,7 +39,17 @@ func UserRegister(c *gin.Context) {
 		c.ShouldBind(&User)
 	}
 
-	err := db.Debug().Create(&User).Error
+	User.FollowerCount = 0
+	User.FollowingCount = 0
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&User).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.UserRegistered, ActorID: User.ID, Payload: models.UserSummary{ID: User.ID, Username: User.Username}})
+	})
 	if err != nil {
 		c.JSON(http.StatusBadRequest, gin.H{
 			"error":   "Bad Request",
@@ -45,10 +59,11 @@ func UserRegister(c *gin.Context) {
 	}
 
 	c.JSON(http.StatusCreated, gin.H{
-		"id":        User.ID,
-		"email":     User.Email,
-		"full_name": User.Username,
-		"age":       User.Age,
+		"id":         User.ID,
+		"email":      User.Email,
+		"full_name":  User.Username,
+		"age":        User.Age,
+		"is_private": User.IsPrivate,
 	})
 }
 
@@ -0,0 +1,62 @@
+package controllers
+
+import (
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// visibleUsersSQL selects the IDs of the users whose content a viewer may
+// see: users with public accounts, the viewer, and private accounts the
+// viewer follows. Users who blocked the viewer are left out.
+const visibleUsersSQL = `SELECT id FROM users WHERE (NOT is_private OR id = ? OR id IN (` + followedUsersSQL + `))
+AND id NOT IN (` + blockersSQL + `)`
+
+// visibleOwners limits a query to the rows whose owner, held in column, the
+// viewer may see. Every read of photos, comments, social media and follow
+// lists goes through it, so private accounts and blocks are handled the same
+// way everywhere.
+func visibleOwners(column string, viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where(column+" IN (?)", gorm.Expr(visibleUsersSQL, viewerID, viewerID, viewerID))
+	}
+}
+
+// canSeeUser reports whether the viewer may see the content of the user.
+func canSeeUser(db *gorm.DB, userID, viewerID uint) (bool, error) {
+	var count int64
+	err := db.Model(&models.User{}).Scopes(visibleOwners("users.id", viewerID)).Where("users.id = ?", userID).Count(&count).Error
+	return count > 0, err
+}
+
+// listablePhotos limits a photo query to the photos that may show up in the
+// viewer's listings: published photos that are public, followers-only photos
+// of users the viewer follows, and everything the viewer owns. Photos of
+// private accounts the viewer doesn't follow and of users who blocked the
+// viewer never show up.
+func listablePhotos(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("photos.status = ? AND (photos.user_id = ? OR photos.visibility = ? OR (photos.visibility = ? AND photos.user_id IN (?)))",
+			models.PhotoPublished, viewerID, models.VisibilityPublic, models.VisibilityFollowers, gorm.Expr(followedUsersSQL, viewerID)).
+			Scopes(visibleOwners("photos.user_id", viewerID))
+	}
+}
+
+// viewablePhotos limits a photo query to the photos the viewer may open by
+// ID. Owners can always open their own photos, including drafts, while
+// everyone else also gets published unlisted photos of users they may see.
+func viewablePhotos(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("(photos.user_id = ? OR (photos.status = ? AND (photos.visibility IN ? OR (photos.visibility = ? AND photos.user_id IN (?)))))",
+			viewerID, models.PhotoPublished, []string{models.VisibilityPublic, models.VisibilityUnlisted}, models.VisibilityFollowers, gorm.Expr(followedUsersSQL, viewerID)).
+			Scopes(visibleOwners("photos.user_id", viewerID))
+	}
+}
+
+// onViewablePhotos limits a comment query to the comments on photos the
+// viewer may open.
+func onViewablePhotos(viewerID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		photoIDs := db.Session(&gorm.Session{NewDB: true}).Model(&models.Photo{}).Select("photos.id").Scopes(viewablePhotos(viewerID))
+		return db.Where("comments.photo_id IN (?)", photoIDs)
+	}
+}
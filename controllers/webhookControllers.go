@@ -0,0 +1,367 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"github.com/dgrijalva/jwt-go"
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/database"
+	"tesjwt.go/events"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+	"tesjwt.go/webhooks"
+)
+
+type UpdateWebhookReq struct {
+	URL        string   `json:"url" form:"url"`
+	EventTypes []string `json:"event_types" form:"event_types"`
+	Active     *bool    `json:"active" form:"active"`
+}
+
+type DeliveryPage struct {
+	Data []models.WebhookDelivery `json:"data"`
+	helpers.Page
+	Total int64 `json:"total"`
+}
+
+// CreateWebhook godoc
+// @Summary Create webhook
+// @Description Register an endpoint that gets the subscribed events POSTed to it. Webhooks of regular users get the events that involve the user, the ones of admins get every event. Every delivery is signed: the X-Webhook-Signature header holds sha256= followed by the hex encoded HMAC-SHA256 of the X-Webhook-Timestamp header, a dot and the body, keyed with the webhook's secret. The secret is only returned here. Failed deliveries are retried with exponential backoff and marked dead after 8 attempts.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param url query string true "url"
+// @Param event_types query []string true "events to receive, such as photo.created, comment.deleted or user.registered"
+// @Security BearerAuth
+// @Success 201 {object} models.Webhook "Create webhook success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Router /webhooks [post]
+func CreateWebhook(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	Webhook := models.Webhook{}
+	userID := uint(userData["id"].(float64))
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&Webhook)
+	} else {
+		c.ShouldBind(&Webhook)
+	}
+
+	Webhook = models.Webhook{
+		UserID:     userID,
+		URL:        Webhook.URL,
+		Secret:     webhooks.NewSecret(),
+		EventTypes: Webhook.EventTypes,
+		Active:     true,
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Create(&Webhook).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.WebhookCreated, ActorID: userID, Payload: withoutSecret(Webhook)})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusCreated, Webhook)
+}
+
+// GetWebhooks godoc
+// @Summary Get webhooks
+// @Description Get the user's webhooks, without their secrets
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Security BearerAuth
+// @Success 200 {object} []models.Webhook "Get webhooks success"
+// @Failure 401 "Unauthorized"
+// @Router /webhooks [get]
+func FindWebhook(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Webhook := []models.Webhook{}
+	userID := uint(userData["id"].(float64))
+
+	err := db.Debug().Where("user_id = ?", userID).Order("id").Find(&Webhook).Error
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	for i := range Webhook {
+		Webhook[i] = withoutSecret(Webhook[i])
+	}
+
+	c.JSON(http.StatusOK, Webhook)
+}
+
+// UpdateWebhook godoc
+// @Summary Update webhook
+// @Description Change the URL or the events of the webhook identified by given id, or pause it by making it inactive. Deliveries queued while a webhook is inactive are marked dead.
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhookID path int true "ID of the webhook"
+// @Param webhook body UpdateWebhookReq true "Fields to change"
+// @Security BearerAuth
+// @Success 200 {object} models.Webhook "Update webhook success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Webhook Not Found"
+// @Router /webhooks/{webhookID} [put]
+func UpdateWebhook(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	contentType := helpers.GetContentType(c)
+	req := UpdateWebhookReq{}
+	Webhook := models.Webhook{}
+
+	WebhookID, _ := strconv.Atoi(c.Param("webhookID"))
+	userID := uint(userData["id"].(float64))
+
+	if abortWebhookNotFound(c, db.Where("user_id = ?", userID).First(&Webhook, WebhookID).Error) {
+		return
+	}
+
+	if contentType == appJSON {
+		c.ShouldBindJSON(&req)
+	} else {
+		c.ShouldBind(&req)
+	}
+
+	if req.URL != "" {
+		Webhook.URL = req.URL
+	}
+	if req.EventTypes != nil {
+		Webhook.EventTypes = req.EventTypes
+	}
+	if req.Active != nil {
+		Webhook.Active = *req.Active
+	}
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Debug().Save(&Webhook).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.WebhookUpdated, ActorID: userID, Payload: withoutSecret(Webhook)})
+	})
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, withoutSecret(Webhook))
+}
+
+// DeleteWebhook godoc
+// @Summary Delete webhook
+// @Description Delete the webhook identified by given id together with its delivery log
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhookID path int true "ID of the webhook"
+// @Security BearerAuth
+// @Success 200 {string} string "Delete webhook success"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Webhook Not Found"
+// @Router /webhooks/{webhookID} [delete]
+func DeleteWebhook(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+
+	WebhookID, _ := strconv.Atoi(c.Param("webhookID"))
+	userID := uint(userData["id"].(float64))
+
+	err := db.Transaction(func(tx *gorm.DB) error {
+		Webhook := models.Webhook{}
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("user_id = ?", userID).First(&Webhook, WebhookID).Error
+		if err != nil {
+			return err
+		}
+
+		err = tx.Debug().Delete(&Webhook).Error
+		if err != nil {
+			return err
+		}
+
+		return outbox.Record(tx, events.Event{Type: events.WebhookDeleted, ActorID: userID, Payload: withoutSecret(Webhook)})
+	})
+	if abortWebhookNotFound(c, err) {
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"message": "Webhook deleted",
+	})
+}
+
+// GetWebhookDeliveries godoc
+// @Summary Get webhook deliveries
+// @Description Get the delivery log of the webhook identified by given id, most recent first
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhookID path int true "ID of the webhook"
+// @Param status query string false "pending, delivered or dead"
+// @Param page query int false "page number, starting at 1"
+// @Param limit query int false "deliveries per page, at most 100"
+// @Security BearerAuth
+// @Success 200 {object} DeliveryPage "Get webhook deliveries success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Webhook Not Found"
+// @Router /webhooks/{webhookID}/deliveries [get]
+func FindWebhookDelivery(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Delivery := []models.WebhookDelivery{}
+
+	WebhookID, _ := strconv.Atoi(c.Param("webhookID"))
+	userID := uint(userData["id"].(float64))
+	status := c.Query("status")
+	page := helpers.GetPage(c)
+
+	if status != "" && !govalidator.IsIn(status, models.DeliveryPending, models.DeliveryDelivered, models.DeliveryDead) {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "status must be pending, delivered or dead",
+		})
+		return
+	}
+
+	if abortWebhookNotFound(c, db.Where("user_id = ?", userID).First(&models.Webhook{}, WebhookID).Error) {
+		return
+	}
+
+	query := db.Model(&models.WebhookDelivery{}).Where("webhook_id = ?", WebhookID)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	query = query.Session(&gorm.Session{})
+
+	var total int64
+	err := query.Count(&total).Error
+	if err == nil {
+		err = query.Debug().Order("id DESC").Scopes(page.Paginate).Find(&Delivery).Error
+	}
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, DeliveryPage{
+		Data:  Delivery,
+		Page:  page,
+		Total: total,
+	})
+}
+
+// RetryWebhookDelivery godoc
+// @Summary Retry webhook delivery
+// @Description Queue a dead delivery of the webhook identified by given id again, with a fresh set of attempts
+// @Tags webhook
+// @Accept json
+// @Produce json
+// @Param webhookID path int true "ID of the webhook"
+// @Param deliveryID path int true "ID of the delivery"
+// @Security BearerAuth
+// @Success 200 {object} models.WebhookDelivery "Retry webhook delivery success"
+// @Failure 400 "Bad Request"
+// @Failure 401 "Unauthorized"
+// @Failure 404 "Delivery Not Found"
+// @Router /webhooks/{webhookID}/deliveries/{deliveryID}/retry [post]
+func RetryWebhookDelivery(c *gin.Context) {
+	db := database.GetDB()
+	userData := c.MustGet("userData").(jwt.MapClaims)
+	Delivery := models.WebhookDelivery{}
+
+	WebhookID, _ := strconv.Atoi(c.Param("webhookID"))
+	DeliveryID, _ := strconv.Atoi(c.Param("deliveryID"))
+	userID := uint(userData["id"].(float64))
+
+	err := db.Where("webhook_id = ? AND webhook_id IN (SELECT id FROM webhooks WHERE user_id = ?)", WebhookID, userID).First(&Delivery, DeliveryID).Error
+	if abortWebhookNotFound(c, err) {
+		return
+	}
+
+	if Delivery.Status != models.DeliveryDead {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": "only dead deliveries can be retried",
+		})
+		return
+	}
+
+	now := time.Now()
+	Delivery.Status = models.DeliveryPending
+	Delivery.Attempts = 0
+	Delivery.NextAttemptAt = &now
+
+	err = db.Debug().Model(&Delivery).Select("status", "attempts", "next_attempt_at").Updates(&Delivery).Error
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+		return
+	}
+
+	c.JSON(http.StatusOK, Delivery)
+}
+
+// withoutSecret blanks the secret of a webhook, which is only shown once
+// when the webhook is created.
+func withoutSecret(webhook models.Webhook) models.Webhook {
+	webhook.Secret = ""
+	return webhook
+}
+
+// abortWebhookNotFound responds to a failed lookup of one of the user's
+// webhooks or deliveries. It reports whether the request was aborted.
+func abortWebhookNotFound(c *gin.Context, err error) bool {
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, gorm.ErrRecordNotFound):
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error":   "Data Not Found",
+			"message": "webhook doesn't exist",
+		})
+	default:
+		c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+			"error":   "Bad Request",
+			"message": err.Error(),
+		})
+	}
+
+	return true
+}
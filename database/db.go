@@ -32,7 +32,26 @@ func StartDB() {
 	}
 
 	fmt.Println("sukses koneksi ke database")
-	db.Debug().AutoMigrate(models.User{}, models.SocialMedia{}, models.Photo{}, models.Comment{})
+
+	// Comments left behind by photos deleted before comments had a foreign key
+	// would keep the constraint from being created. They are only cleaned up
+	// while the constraint is missing, which makes this a one-off.
+	migrator := db.Migrator()
+	if migrator.HasTable(&models.Comment{}) && migrator.HasTable(&models.Photo{}) &&
+		!migrator.HasConstraint(&models.Comment{}, "Photo") && !migrator.HasConstraint(&models.Photo{}, "Comments") {
+		result := db.Debug().Exec("DELETE FROM comments WHERE photo_id NOT IN (SELECT id FROM photos)")
+		if result.Error != nil {
+			log.Println("error removing comments of deleted photos :", result.Error)
+		} else {
+			log.Printf("removed %d comments of deleted photos", result.RowsAffected)
+		}
+	}
+
+	db.Debug().AutoMigrate(models.User{}, models.SocialMedia{}, models.Photo{}, models.Comment{}, models.Mention{}, models.CommentRevision{}, models.Reaction{}, models.ReactionCount{}, models.Like{}, models.CommentSettings{}, models.Follow{}, models.FeedItem{}, models.Block{}, models.Mute{}, models.SavedPhoto{}, models.Notification{}, models.NotificationPreference{}, models.Conversation{}, models.ConversationMember{}, models.Message{}, models.Story{}, models.StoryView{}, models.Webhook{}, models.WebhookDelivery{}, models.OutboxEvent{})
+
+	// Photos published before publish times were recorded are ordered in
+	// feeds by when they were posted.
+	db.Debug().Exec("UPDATE photos SET published_at = created_at WHERE status = ? AND published_at IS NULL", models.PhotoPublished)
 }
 
 func GetDB() *gorm.DB {
@@ -0,0 +1,156 @@
+package events
+
+import (
+	"log"
+	"sync"
+	"time"
+)
+
+// Event types. The payload of each event is the record it is about.
+const (
+	// CommentCreated carries the new models.Comment with its mentions. Held
+	// comments fire it once they are approved rather than when posted.
+	CommentCreated = "comment.created"
+	// CommentUpdated carries the edited models.Comment with its mentions.
+	CommentUpdated = "comment.updated"
+	// CommentDeleted carries the models.Comment moved to the trash. Its
+	// replies go with it without events of their own.
+	CommentDeleted = "comment.deleted"
+	// CommentRestored carries the models.Comment taken out of the trash. The
+	// replies restored with it get no events of their own.
+	CommentRestored = "comment.restored"
+	// CommentModerated carries the models.Comment whose status the owner of
+	// the photo changed.
+	CommentModerated = "comment.moderated"
+	// CommentSettingsUpdated carries the models.CommentSettings of a photo.
+	CommentSettingsUpdated = "comment_settings.updated"
+	// PhotoCreated carries the new models.Photo, whether it was published
+	// right away or saved as a draft or scheduled.
+	PhotoCreated = "photo.created"
+	// PhotoUpdated carries the edited models.Photo as stored. Its mentions
+	// are only set when the caption was sent.
+	PhotoUpdated = "photo.updated"
+	// PhotoPublished carries the models.Photo that went live, with its
+	// mentions. It fires when a photo is posted, when a draft is published
+	// and when a scheduled photo goes out.
+	PhotoPublished = "photo.published"
+	// PhotoDeleted carries the models.Photo moved to the trash.
+	PhotoDeleted = "photo.deleted"
+	// PhotoRestored carries the models.Photo taken out of the trash.
+	PhotoRestored = "photo.restored"
+	// PhotoLiked carries the models.Like.
+	PhotoLiked = "photo.liked"
+	// PhotoUnliked carries the models.Like that was removed.
+	PhotoUnliked = "photo.unliked"
+	// PhotoSaved carries the models.SavedPhoto, also when a saved photo is
+	// moved to another collection.
+	PhotoSaved = "photo.saved"
+	// PhotoUnsaved carries the models.SavedPhoto that was removed.
+	PhotoUnsaved = "photo.unsaved"
+	// ReactionAdded and ReactionRemoved carry the models.Reaction.
+	ReactionAdded   = "reaction.added"
+	ReactionRemoved = "reaction.removed"
+	// SocialMediaCreated, SocialMediaUpdated, SocialMediaDeleted and
+	// SocialMediaRestored carry the models.SocialMedia.
+	SocialMediaCreated  = "social_media.created"
+	SocialMediaUpdated  = "social_media.updated"
+	SocialMediaDeleted  = "social_media.deleted"
+	SocialMediaRestored = "social_media.restored"
+	// StoryCreated carries the new models.Story.
+	StoryCreated = "story.created"
+	// UserFollowed carries the models.Follow, which is pending when the
+	// followed account is private.
+	UserFollowed = "user.followed"
+	// UserUnfollowed carries the models.Follow that was removed, or the
+	// follow request that was withdrawn.
+	UserUnfollowed = "user.unfollowed"
+	// FollowRequestApproved carries the models.Follow that was accepted,
+	// whether by the followee or by them making their account public.
+	FollowRequestApproved = "follow_request.approved"
+	// FollowRequestRejected carries the pending models.Follow that was
+	// turned down.
+	FollowRequestRejected = "follow_request.rejected"
+	// UserBlocked and UserUnblocked carry the models.Block. The follows a
+	// block ends get no events of their own.
+	UserBlocked   = "user.blocked"
+	UserUnblocked = "user.unblocked"
+	// UserMuted and UserUnmuted carry the models.Mute.
+	UserMuted   = "user.muted"
+	UserUnmuted = "user.unmuted"
+	// UserRegistered carries the models.UserSummary of the new user.
+	UserRegistered = "user.registered"
+	// MessageCreated carries the models.Message sent to a conversation.
+	MessageCreated = "message.created"
+	// ConversationCreated carries the new models.Conversation with its
+	// members.
+	ConversationCreated = "conversation.created"
+	// ConversationRead carries the models.ConversationMember whose read
+	// receipt moved.
+	ConversationRead = "conversation.read"
+	// WebhookCreated, WebhookUpdated and WebhookDeleted carry the
+	// models.Webhook, without its secret.
+	WebhookCreated = "webhook.created"
+	WebhookUpdated = "webhook.updated"
+	WebhookDeleted = "webhook.deleted"
+	// NotificationCreated carries a models.Notification that was just
+	// stored.
+	NotificationCreated = "notification.created"
+)
+
+// Event is something that happened in the app that other parts of it may
+// want to react to.
+type Event struct {
+	// ID is the ID of the event in the outbox, or zero for events that were
+	// published without going through it.
+	ID         uint
+	Type       string
+	ActorID    uint
+	Payload    interface{}
+	OccurredAt time.Time
+}
+
+// Handler reacts to an event.
+type Handler func(Event)
+
+var (
+	mu       sync.RWMutex
+	handlers = map[string][]Handler{}
+)
+
+// Subscribe registers handler for the events of the given type.
+func Subscribe(eventType string, handler Handler) {
+	mu.Lock()
+	defer mu.Unlock()
+
+	handlers[eventType] = append(handlers[eventType], handler)
+}
+
+// Publish hands an event to the handlers of its type, in the order they
+// subscribed. It has to be called once the change the event is about has
+// been committed, which is why writes record their events in the outbox and
+// leave publishing them to its dispatcher. Handlers run in the caller's
+// goroutine, and a handler that panics is logged without keeping the event
+// from the others.
+func Publish(event Event) {
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = time.Now()
+	}
+
+	mu.RLock()
+	subscribed := handlers[event.Type]
+	mu.RUnlock()
+
+	for _, handler := range subscribed {
+		run(handler, event)
+	}
+}
+
+func run(handler Handler, event Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("error handling %s event : %v", event.Type, r)
+		}
+	}()
+
+	handler(event)
+}
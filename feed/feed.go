@@ -0,0 +1,78 @@
+package feed
+
+import (
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/helpers"
+	"tesjwt.go/models"
+)
+
+// BackfillSize is how many of the latest photos of a user are copied into
+// the feed of someone who starts following them.
+const BackfillSize = 100
+
+// photoIDsSQL selects the photos that belong in a user's feed. Photos that
+// were fanned out on publish are read from the user's feed items. Photos of
+// followed users that were not, because their author had too many followers
+// or because they were not shared with followers when published, are read
+// straight from the photos table instead.
+const photoIDsSQL = `SELECT photo_id FROM feed_items WHERE user_id = ?
+UNION ALL
+SELECT id FROM photos WHERE NOT fanned_out AND user_id IN (SELECT followee_id FROM follows WHERE follower_id = ? AND status = 'accepted')`
+
+// Photos limits a photo query to the photos in the user's feed. Visibility
+// is not checked here, so photos that were made private after they were
+// fanned out have to be filtered by the caller.
+func Photos(userID uint) func(*gorm.DB) *gorm.DB {
+	return func(db *gorm.DB) *gorm.DB {
+		return db.Where("photos.id IN (?)", gorm.Expr(photoIDsSQL, userID, userID))
+	}
+}
+
+// FanOut copies a newly published photo into the feeds of its author's
+// followers. Photos that are not shared with followers, and photos of users
+// with more than helpers.FeedFanOutLimit followers, are left to be read from
+// the photos table.
+func FanOut(tx *gorm.DB, photo models.Photo) error {
+	if photo.Status != models.PhotoPublished || photo.PublishedAt == nil {
+		return nil
+	}
+	if photo.Visibility != models.VisibilityPublic && photo.Visibility != models.VisibilityFollowers {
+		return nil
+	}
+
+	author := models.User{}
+	err := tx.Select("id", "follower_count").First(&author, photo.UserID).Error
+	if err != nil {
+		return err
+	}
+	if author.FollowerCount > helpers.FeedFanOutLimit() {
+		return nil
+	}
+
+	err = tx.Exec(`INSERT INTO feed_items (created_at, user_id, photo_id, author_id, published_at)
+SELECT ?, follower_id, ?, ?, ? FROM follows WHERE followee_id = ? AND status = ?
+ON CONFLICT DO NOTHING`, time.Now(), photo.ID, photo.UserID, *photo.PublishedAt, photo.UserID, models.FollowAccepted).Error
+	if err != nil {
+		return err
+	}
+
+	return tx.Model(&models.Photo{}).Where("id = ?", photo.ID).UpdateColumn("fanned_out", true).Error
+}
+
+// Backfill copies the latest fanned out photos of a user into the feed of a
+// new follower, so they don't have to wait for the next post to see them.
+func Backfill(tx *gorm.DB, followerID, followeeID uint) error {
+	return tx.Exec(`INSERT INTO feed_items (created_at, user_id, photo_id, author_id, published_at)
+SELECT ?, ?, id, user_id, published_at FROM photos
+WHERE user_id = ? AND fanned_out AND status = ? AND published_at IS NOT NULL AND deleted_at IS NULL
+ORDER BY published_at DESC LIMIT ?
+ON CONFLICT DO NOTHING`, time.Now(), followerID, followeeID, models.PhotoPublished, BackfillSize).Error
+}
+
+// Remove takes the photos of a user out of the feed of someone who stopped
+// following them.
+func Remove(tx *gorm.DB, followerID, followeeID uint) error {
+	return tx.Where("user_id = ? AND author_id = ?", followerID, followeeID).Delete(&models.FeedItem{}).Error
+}
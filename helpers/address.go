@@ -0,0 +1,67 @@
+package helpers
+
+import (
+	"errors"
+	"net"
+	"net/url"
+	"syscall"
+)
+
+// ErrInternalAddress is returned for addresses that point back into the
+// network the server runs in.
+var ErrInternalAddress = errors.New("must not point to an internal network")
+
+// IsPublicIP reports whether ip can be reached from the outside, so it isn't
+// loopback, private, link-local, multicast or unspecified.
+func IsPublicIP(ip net.IP) bool {
+	return !(ip.IsLoopback() || ip.IsPrivate() || ip.IsUnspecified() ||
+		ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() ||
+		ip.IsInterfaceLocalMulticast() || ip.IsMulticast())
+}
+
+// CheckPublicURL resolves the host of rawURL and fails when any of its
+// addresses isn't public.
+func CheckPublicURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return err
+	}
+
+	host := u.Hostname()
+	if ip := net.ParseIP(host); ip != nil {
+		if !IsPublicIP(ip) {
+			return ErrInternalAddress
+		}
+		return nil
+	}
+
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return errors.New("host " + host + " can't be resolved")
+	}
+	for _, ip := range ips {
+		if !IsPublicIP(ip) {
+			return ErrInternalAddress
+		}
+	}
+
+	return nil
+}
+
+// DialPublicOnly is a net.Dialer Control function that refuses connections
+// to addresses that aren't public. It runs after the host is resolved, so a
+// name that resolves differently than it did when it was checked is still
+// caught.
+func DialPublicOnly(network, address string, _ syscall.RawConn) error {
+	host, _, err := net.SplitHostPort(address)
+	if err != nil {
+		return err
+	}
+
+	ip := net.ParseIP(host)
+	if ip == nil || !IsPublicIP(ip) {
+		return ErrInternalAddress
+	}
+
+	return nil
+}
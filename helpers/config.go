@@ -0,0 +1,32 @@
+package helpers
+
+import (
+	"os"
+	"strconv"
+	"time"
+)
+
+// TrashRetention is how long deleted photos, comments and social media can be
+// restored before they are purged. It is read from TRASH_RETENTION_DAYS and
+// defaults to 30 days.
+func TrashRetention() time.Duration {
+	days, err := strconv.Atoi(os.Getenv("TRASH_RETENTION_DAYS"))
+	if err != nil || days <= 0 {
+		days = 30
+	}
+
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// FeedFanOutLimit is the follower count up to which new photos are copied
+// into the feed of every follower. Photos of users with more followers are
+// read straight from the photos table instead. It is read from
+// FEED_FANOUT_LIMIT and defaults to 10000.
+func FeedFanOutLimit() int {
+	limit, err := strconv.Atoi(os.Getenv("FEED_FANOUT_LIMIT"))
+	if err != nil || limit < 0 {
+		limit = 10000
+	}
+
+	return limit
+}
@@ -0,0 +1,38 @@
+package helpers
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+)
+
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// EncodeCursor packs the sort key of the last item on a page into an opaque
+// cursor the client sends back to get the next page.
+func EncodeCursor(values ...interface{}) string {
+	raw, _ := json.Marshal(values)
+	return base64.RawURLEncoding.EncodeToString(raw)
+}
+
+// DecodeCursor unpacks a cursor made by EncodeCursor into values, which have
+// to be pointers to the same types the cursor was made from.
+func DecodeCursor(cursor string, values ...interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return ErrInvalidCursor
+	}
+
+	fields := []json.RawMessage{}
+	if json.Unmarshal(raw, &fields) != nil || len(fields) != len(values) {
+		return ErrInvalidCursor
+	}
+
+	for i, field := range fields {
+		if json.Unmarshal(field, values[i]) != nil {
+			return ErrInvalidCursor
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,130 @@
+package helpers
+
+import "unicode/utf8"
+
+// Code points that join, vary and modify emoji into sequences.
+const (
+	zeroWidthJoiner   = '\u200D'
+	textPresentation  = '\uFE0E'
+	emojiPresentation = '\uFE0F'
+	combiningKeycap   = '\u20E3'
+	cancelTag         = '\U000E007F'
+)
+
+// maxEmojiRunes caps the length of a sequence. The longest emoji in use,
+// couples with a skin tone for each person, are 10 code points.
+const maxEmojiRunes = 16
+
+// pictographicRanges are the code points that are emoji on their own, or
+// become emoji when followed by the emoji presentation selector.
+var pictographicRanges = [][2]rune{
+	{0x00A9, 0x00A9}, {0x00AE, 0x00AE}, {0x203C, 0x203C}, {0x2049, 0x2049},
+	{0x2122, 0x2122}, {0x2139, 0x2139}, {0x2194, 0x2199}, {0x21A9, 0x21AA},
+	{0x231A, 0x231B}, {0x2328, 0x2328}, {0x23CF, 0x23CF}, {0x23E9, 0x23F3},
+	{0x23F8, 0x23FA}, {0x24C2, 0x24C2}, {0x25AA, 0x25AB}, {0x25B6, 0x25B6},
+	{0x25C0, 0x25C0}, {0x25FB, 0x25FE}, {0x2600, 0x27BF}, {0x2934, 0x2935},
+	{0x2B05, 0x2B07}, {0x2B1B, 0x2B1C}, {0x2B50, 0x2B50}, {0x2B55, 0x2B55},
+	{0x3030, 0x3030}, {0x303D, 0x303D}, {0x3297, 0x3297}, {0x3299, 0x3299},
+	{0x1F000, 0x1F1E5}, {0x1F200, 0x1F3FA}, {0x1F400, 0x1FAFF},
+}
+
+func isPictographic(r rune) bool {
+	for _, bounds := range pictographicRanges {
+		if r >= bounds[0] && r <= bounds[1] {
+			return true
+		}
+	}
+
+	return false
+}
+
+func isRegionalIndicator(r rune) bool {
+	return r >= 0x1F1E6 && r <= 0x1F1FF
+}
+
+func isSkinTone(r rune) bool {
+	return r >= 0x1F3FB && r <= 0x1F3FF
+}
+
+func isTag(r rune) bool {
+	return r >= 0xE0020 && r <= 0xE007E
+}
+
+func isKeycapBase(r rune) bool {
+	return r >= '0' && r <= '9' || r == '#' || r == '*'
+}
+
+// IsEmoji reports whether s is a single emoji: one pictograph with an
+// optional presentation selector, skin tone or tag sequence, several of them
+// joined with zero width joiners, a keycap, or a flag made of two regional
+// indicators.
+func IsEmoji(s string) bool {
+	if !utf8.ValidString(s) || utf8.RuneCountInString(s) > maxEmojiRunes {
+		return false
+	}
+
+	runes := []rune(s)
+	if len(runes) == 0 {
+		return false
+	}
+
+	if isRegionalIndicator(runes[0]) {
+		return len(runes) == 2 && isRegionalIndicator(runes[1])
+	}
+
+	i := 0
+	for {
+		n := emojiElement(runes[i:])
+		if n == 0 {
+			return false
+		}
+
+		i += n
+		if i == len(runes) {
+			return true
+		}
+		if runes[i] != zeroWidthJoiner || i+1 == len(runes) {
+			return false
+		}
+		i++
+	}
+}
+
+// emojiElement returns how many runes at the start of runes make up one
+// emoji that can be joined to others, or zero when they don't start with
+// one.
+func emojiElement(runes []rune) int {
+	if isKeycapBase(runes[0]) {
+		i := 1
+		if i < len(runes) && runes[i] == emojiPresentation {
+			i++
+		}
+		if i < len(runes) && runes[i] == combiningKeycap {
+			return i + 1
+		}
+		return 0
+	}
+
+	if !isPictographic(runes[0]) {
+		return 0
+	}
+
+	i := 1
+	if i < len(runes) && (runes[i] == emojiPresentation || runes[i] == textPresentation || isSkinTone(runes[i])) {
+		i++
+	}
+
+	// Tag sequences such as the flags of England or Scotland end with a
+	// cancel tag.
+	if i < len(runes) && isTag(runes[i]) {
+		for i < len(runes) && isTag(runes[i]) {
+			i++
+		}
+		if i < len(runes) && runes[i] == cancelTag {
+			return i + 1
+		}
+		return 0
+	}
+
+	return i
+}
@@ -0,0 +1,153 @@
+package helpers
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/schema"
+)
+
+// SortKey is a column a list is ordered by.
+type SortKey struct {
+	Column string
+	Desc   bool
+}
+
+// Keyset pages through a list with opaque cursors rather than offsets, so
+// pages stay cheap however deep the client goes and rows added meanwhile
+// don't shift them. The list is ordered by Keys, which together have to be
+// unique and can't be null, so the last one is usually the ID.
+type Keyset struct {
+	Limit int
+	After string
+	Keys  []SortKey
+}
+
+// GetKeyset reads the limit and after query parameters of a list ordered by
+// keys. Limit falls back to DefaultPageSize and is capped at MaxPageSize.
+func GetKeyset(c *gin.Context, keys ...SortKey) Keyset {
+	return Keyset{Limit: GetLimit(c), After: c.Query("after"), Keys: keys}
+}
+
+// Find loads the page into dest, a pointer to a slice of models, and returns
+// the cursor of the next page, which is empty on the last page. An after
+// cursor that wasn't made for the same keys fails with ErrInvalidCursor.
+func (k Keyset) Find(query *gorm.DB, dest interface{}) (string, error) {
+	stmt := &gorm.Statement{DB: query}
+	err := stmt.Parse(dest)
+	if err != nil {
+		return "", err
+	}
+
+	fields := make([]*schema.Field, len(k.Keys))
+	orders := make([]string, len(k.Keys))
+	for i, key := range k.Keys {
+		column := key.Column[strings.LastIndex(key.Column, ".")+1:]
+		fields[i] = stmt.Schema.LookUpField(column)
+		if fields[i] == nil {
+			return "", fmt.Errorf("%s can't be sorted by %s", stmt.Schema.Table, key.Column)
+		}
+
+		orders[i] = key.Column
+		if key.Desc {
+			orders[i] += " DESC"
+		}
+	}
+
+	if k.After != "" {
+		values, err := k.decode(fields)
+		if err != nil {
+			return "", err
+		}
+		query = query.Where(k.after(values))
+	}
+
+	// One row past the page tells whether there is a next page.
+	err = query.Order(strings.Join(orders, ", ")).Limit(k.Limit + 1).Find(dest).Error
+	if err != nil {
+		return "", err
+	}
+
+	rows := reflect.ValueOf(dest).Elem()
+	if rows.Len() <= k.Limit {
+		return "", nil
+	}
+	rows.SetLen(k.Limit)
+
+	last := rows.Index(k.Limit - 1)
+	values := make([]interface{}, len(fields))
+	for i, field := range fields {
+		values[i], _ = field.ValueOf(context.Background(), last)
+	}
+
+	return EncodeCursor(values...), nil
+}
+
+// decode unpacks the after cursor into values of the types of the key
+// fields.
+func (k Keyset) decode(fields []*schema.Field) ([]interface{}, error) {
+	pointers := make([]interface{}, len(fields))
+	for i, field := range fields {
+		pointers[i] = reflect.New(field.FieldType).Interface()
+	}
+
+	err := DecodeCursor(k.After, pointers...)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, len(fields))
+	for i, pointer := range pointers {
+		values[i] = reflect.ValueOf(pointer).Elem().Interface()
+	}
+
+	return values, nil
+}
+
+// after builds the condition selecting the rows that come after the row
+// with the given key values: the ones past it on the first key, or tied on
+// the first key and past it on the second, and so on.
+func (k Keyset) after(values []interface{}) clause.Expr {
+	alternatives := make([]string, len(k.Keys))
+	args := []interface{}{}
+	for i, key := range k.Keys {
+		conditions := []string{}
+		for j := 0; j < i; j++ {
+			conditions = append(conditions, k.Keys[j].Column+" = ?")
+			args = append(args, values[j])
+		}
+
+		operator := " > ?"
+		if key.Desc {
+			operator = " < ?"
+		}
+		conditions = append(conditions, key.Column+operator)
+		args = append(args, values[i])
+
+		alternatives[i] = "(" + strings.Join(conditions, " AND ") + ")"
+	}
+
+	return gorm.Expr("("+strings.Join(alternatives, " OR ")+")", args...)
+}
+
+// SetNextLink points the Link header of the response at the next page of
+// the list, keeping the other query parameters of the request.
+func SetNextLink(c *gin.Context, cursor string, limit int) {
+	if cursor == "" {
+		return
+	}
+
+	query := c.Request.URL.Query()
+	query.Set("after", cursor)
+	query.Set("limit", strconv.Itoa(limit))
+
+	next := url.URL{Path: c.Request.URL.Path, RawQuery: query.Encode()}
+	c.Header("Link", fmt.Sprintf("<%s>; rel=\"next\"", next.String()))
+}
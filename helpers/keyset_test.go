@@ -0,0 +1,211 @@
+package helpers
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/schema"
+)
+
+type keysetRow struct {
+	ID          uint
+	Title       string
+	LikeCount   int
+	PublishedAt time.Time
+}
+
+// dryRunDB builds SQL without connecting to a database.
+func dryRunDB(t *testing.T) *gorm.DB {
+	t.Helper()
+
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=localhost"}), &gorm.Config{DryRun: true, DisableAutomaticPing: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return db
+}
+
+func keysetFields(t *testing.T, keys []SortKey) []*schema.Field {
+	t.Helper()
+
+	s, err := schema.Parse(&keysetRow{}, &sync.Map{}, schema.NamingStrategy{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fields := make([]*schema.Field, len(keys))
+	for i, key := range keys {
+		fields[i] = s.LookUpField(key.Column)
+		if fields[i] == nil {
+			t.Fatalf("no field for %s", key.Column)
+		}
+	}
+
+	return fields
+}
+
+func TestKeysetCursorRoundTrip(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 31, 15, 4, 5, 123456789, time.UTC)
+	row := keysetRow{ID: 42, Title: "sunset, at the beach", LikeCount: 7, PublishedAt: publishedAt}
+
+	tests := []struct {
+		name string
+		keys []SortKey
+		want []interface{}
+	}{
+		{"int", []SortKey{{Column: "id"}}, []interface{}{uint(42)}},
+		{"string", []SortKey{{Column: "title"}, {Column: "id"}}, []interface{}{"sunset, at the beach", uint(42)}},
+		{"time", []SortKey{{Column: "published_at", Desc: true}, {Column: "id", Desc: true}}, []interface{}{publishedAt, uint(42)}},
+		{"mixed", []SortKey{{Column: "like_count", Desc: true}, {Column: "title"}, {Column: "published_at"}, {Column: "id"}}, []interface{}{7, "sunset, at the beach", publishedAt, uint(42)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields := keysetFields(t, tt.keys)
+
+			values := make([]interface{}, len(fields))
+			for i, field := range fields {
+				values[i], _ = field.ValueOf(context.Background(), reflect.ValueOf(row))
+			}
+
+			keyset := Keyset{After: EncodeCursor(values...), Keys: tt.keys}
+			got, err := keyset.decode(fields)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if len(got) != len(tt.want) {
+				t.Fatalf("got %d values, want %d", len(got), len(tt.want))
+			}
+			for i := range got {
+				if at, ok := got[i].(time.Time); ok {
+					if !at.Equal(tt.want[i].(time.Time)) {
+						t.Errorf("value %d = %v, want %v", i, at, tt.want[i])
+					}
+					continue
+				}
+				if got[i] != tt.want[i] {
+					t.Errorf("value %d = %#v, want %#v", i, got[i], tt.want[i])
+				}
+			}
+		})
+	}
+}
+
+func TestKeysetDecodeRejectsForeignCursors(t *testing.T) {
+	keys := []SortKey{{Column: "published_at", Desc: true}, {Column: "id", Desc: true}}
+
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "***"},
+		{"not a list", "e30"}, // {}
+		{"too few values", EncodeCursor(uint(1))},
+		{"too many values", EncodeCursor(time.Now(), uint(1), uint(2))},
+		{"wrong types", EncodeCursor("yesterday", uint(1))},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			keyset := Keyset{After: tt.cursor, Keys: keys}
+			_, err := keyset.decode(keysetFields(t, keys))
+			if !errors.Is(err, ErrInvalidCursor) {
+				t.Errorf("err = %v, want ErrInvalidCursor", err)
+			}
+		})
+	}
+}
+
+func TestKeysetAfter(t *testing.T) {
+	publishedAt := time.Date(2024, 1, 31, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name     string
+		keys     []SortKey
+		values   []interface{}
+		wantSQL  string
+		wantVars []interface{}
+	}{
+		{
+			name:     "single ascending key",
+			keys:     []SortKey{{Column: "id"}},
+			values:   []interface{}{uint(5)},
+			wantSQL:  "((id > ?))",
+			wantVars: []interface{}{uint(5)},
+		},
+		{
+			name:     "single descending key",
+			keys:     []SortKey{{Column: "id", Desc: true}},
+			values:   []interface{}{uint(5)},
+			wantSQL:  "((id < ?))",
+			wantVars: []interface{}{uint(5)},
+		},
+		{
+			name:     "descending time with ID tie-break",
+			keys:     []SortKey{{Column: "photos.published_at", Desc: true}, {Column: "photos.id", Desc: true}},
+			values:   []interface{}{publishedAt, uint(5)},
+			wantSQL:  "((photos.published_at < ?) OR (photos.published_at = ? AND photos.id < ?))",
+			wantVars: []interface{}{publishedAt, publishedAt, uint(5)},
+		},
+		{
+			name:     "mixed directions",
+			keys:     []SortKey{{Column: "like_count", Desc: true}, {Column: "title"}, {Column: "id"}},
+			values:   []interface{}{3, "b", uint(5)},
+			wantSQL:  "((like_count < ?) OR (like_count = ? AND title > ?) OR (like_count = ? AND title = ? AND id > ?))",
+			wantVars: []interface{}{3, 3, "b", 3, "b", uint(5)},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			expr := Keyset{Keys: tt.keys}.after(tt.values)
+			if expr.SQL != tt.wantSQL {
+				t.Errorf("SQL = %q, want %q", expr.SQL, tt.wantSQL)
+			}
+			if !reflect.DeepEqual(expr.Vars, tt.wantVars) {
+				t.Errorf("Vars = %#v, want %#v", expr.Vars, tt.wantVars)
+			}
+		})
+	}
+}
+
+func TestKeysetFind(t *testing.T) {
+	db := dryRunDB(t)
+	keys := []SortKey{{Column: "keyset_rows.like_count", Desc: true}, {Column: "keyset_rows.id", Desc: true}}
+
+	tests := []struct {
+		name    string
+		keyset  Keyset
+		wantErr error
+	}{
+		{"first page", Keyset{Limit: 10, Keys: keys}, nil},
+		{"next page", Keyset{Limit: 10, After: EncodeCursor(3, uint(5)), Keys: keys}, nil},
+		{"foreign cursor", Keyset{Limit: 10, After: EncodeCursor("x"), Keys: keys}, ErrInvalidCursor},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rows := []keysetRow{}
+			next, err := tt.keyset.Find(db.Model(&keysetRow{}), &rows)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, want %v", err, tt.wantErr)
+			}
+			if next != "" {
+				t.Errorf("next = %q on an empty page", next)
+			}
+		})
+	}
+
+	_, err := Keyset{Limit: 10, Keys: []SortKey{{Column: "caption"}}}.Find(db.Model(&keysetRow{}), &[]keysetRow{})
+	if err == nil {
+		t.Error("sorting by a column the model doesn't have succeeded")
+	}
+}
@@ -0,0 +1,15 @@
+package helpers
+
+import "strings"
+
+// MatchKeyword returns the first keyword that appears in text, ignoring case.
+func MatchKeyword(text string, keywords []string) (string, bool) {
+	text = strings.ToLower(text)
+	for _, keyword := range keywords {
+		if keyword != "" && strings.Contains(text, strings.ToLower(keyword)) {
+			return keyword, true
+		}
+	}
+
+	return "", false
+}
@@ -0,0 +1,215 @@
+package helpers
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// FieldType is the type of the values of a list field, which query
+// parameters are parsed as.
+type FieldType int
+
+const (
+	FieldNumber FieldType = iota
+	FieldString
+	FieldTime
+	FieldBool
+)
+
+// ListField is a column of a list that clients may filter or sort on.
+type ListField struct {
+	Column string
+	Type   FieldType
+	Filter bool
+	Sort   bool
+}
+
+// ListSpec declares what a list endpoint can be filtered and sorted by,
+// keyed by the names clients use. Filtering on a field takes the values it
+// has to equal, separated by commas, as in ?user_id=3,4. Time fields whose
+// name ends in _at are filtered on a range instead, as in
+// ?created_after=2024-01-31&created_before=2024-02-01. The q parameter
+// looks for text in the Search columns, and sort takes the fields to order
+// by, descending when prefixed with a minus, as in ?sort=-created_at,title.
+type ListSpec struct {
+	Fields map[string]ListField
+	Search []string
+	// DefaultSort is the sort used when the client doesn't ask for one, in
+	// the same syntax.
+	DefaultSort string
+	// ID is a unique column every sort ends with, so pages of the list
+	// follow each other without gaps.
+	ID string
+}
+
+// ListQuery is a filter and sort parsed from the query parameters of a
+// request.
+type ListQuery struct {
+	conditions []func(*gorm.DB) *gorm.DB
+	Sort       []SortKey
+}
+
+// Filter limits a query to the rows matching the filters.
+func (q ListQuery) Filter(db *gorm.DB) *gorm.DB {
+	for _, condition := range q.conditions {
+		db = condition(db)
+	}
+
+	return db
+}
+
+// ParseList reads the filters, search and sort of a list from the query
+// parameters. Filters and sorts on fields the spec doesn't allow, and values
+// that can't be parsed, are rejected. Parameters that aren't fields of the
+// list are left alone.
+func ParseList(c *gin.Context, spec ListSpec) (ListQuery, error) {
+	list := ListQuery{}
+
+	names := make([]string, 0, len(spec.Fields))
+	for name := range spec.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		field := spec.Fields[name]
+		if !field.Filter {
+			continue
+		}
+
+		var err error
+		if field.Type == FieldTime && strings.HasSuffix(name, "_at") {
+			prefix := strings.TrimSuffix(name, "_at")
+			err = list.addRange(c, prefix+"_after", field.Column+" > ?", field)
+			if err == nil {
+				err = list.addRange(c, prefix+"_before", field.Column+" < ?", field)
+			}
+		} else {
+			err = list.addEquals(c, name, field)
+		}
+		if err != nil {
+			return list, err
+		}
+	}
+
+	if text := c.Query("q"); text != "" && len(spec.Search) > 0 {
+		pattern := "%" + escapeLike(text) + "%"
+		conditions := make([]string, len(spec.Search))
+		args := make([]interface{}, len(spec.Search))
+		for i, column := range spec.Search {
+			conditions[i] = column + " ILIKE ?"
+			args[i] = pattern
+		}
+
+		list.conditions = append(list.conditions, func(db *gorm.DB) *gorm.DB {
+			return db.Where("("+strings.Join(conditions, " OR ")+")", args...)
+		})
+	}
+
+	order := c.Query("sort")
+	if order == "" {
+		order = spec.DefaultSort
+	}
+
+	sorted := map[string]bool{}
+	for _, name := range strings.Split(order, ",") {
+		name = strings.TrimSpace(name)
+		desc := strings.HasPrefix(name, "-")
+		name = strings.TrimPrefix(name, "-")
+		if name == "" || sorted[name] {
+			continue
+		}
+
+		field, ok := spec.Fields[name]
+		if !ok || !field.Sort {
+			return list, fmt.Errorf("the list can't be sorted by %s", name)
+		}
+
+		sorted[name] = true
+		list.Sort = append(list.Sort, SortKey{Column: field.Column, Desc: desc})
+	}
+
+	// Ties are broken on the ID in the direction of the last sort field.
+	desc := len(list.Sort) > 0 && list.Sort[len(list.Sort)-1].Desc
+	if len(list.Sort) == 0 || list.Sort[len(list.Sort)-1].Column != spec.ID {
+		list.Sort = append(list.Sort, SortKey{Column: spec.ID, Desc: desc})
+	}
+
+	return list, nil
+}
+
+func (q *ListQuery) addEquals(c *gin.Context, name string, field ListField) error {
+	raw := c.Query(name)
+	if raw == "" {
+		return nil
+	}
+
+	values := []interface{}{}
+	for _, text := range strings.Split(raw, ",") {
+		value, err := parseFieldValue(name, strings.TrimSpace(text), field.Type)
+		if err != nil {
+			return err
+		}
+		values = append(values, value)
+	}
+
+	q.conditions = append(q.conditions, func(db *gorm.DB) *gorm.DB {
+		return db.Where(field.Column+" IN ?", values)
+	})
+	return nil
+}
+
+func (q *ListQuery) addRange(c *gin.Context, param, condition string, field ListField) error {
+	raw := c.Query(param)
+	if raw == "" {
+		return nil
+	}
+
+	value, err := parseFieldValue(param, raw, field.Type)
+	if err != nil {
+		return err
+	}
+
+	q.conditions = append(q.conditions, func(db *gorm.DB) *gorm.DB {
+		return db.Where(condition, value)
+	})
+	return nil
+}
+
+func parseFieldValue(param, text string, fieldType FieldType) (interface{}, error) {
+	switch fieldType {
+	case FieldNumber:
+		value, err := strconv.ParseInt(text, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be a number", param)
+		}
+		return value, nil
+	case FieldTime:
+		for _, layout := range []string{time.RFC3339, "2006-01-02"} {
+			value, err := time.Parse(layout, text)
+			if err == nil {
+				return value, nil
+			}
+		}
+		return nil, fmt.Errorf("%s must be a date such as 2024-01-31 or a time such as 2024-01-31T15:04:05Z", param)
+	case FieldBool:
+		value, err := strconv.ParseBool(text)
+		if err != nil {
+			return nil, fmt.Errorf("%s must be true or false", param)
+		}
+		return value, nil
+	default:
+		return text, nil
+	}
+}
+
+// escapeLike escapes the wildcards of LIKE patterns in text.
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(text)
+}
@@ -0,0 +1,226 @@
+package helpers
+
+import (
+	"net/http/httptest"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+var testListSpec = ListSpec{
+	Fields: map[string]ListField{
+		"id":         {Column: "keyset_rows.id", Type: FieldNumber, Filter: true, Sort: true},
+		"title":      {Column: "keyset_rows.title", Type: FieldString, Filter: true, Sort: true},
+		"like_count": {Column: "keyset_rows.like_count", Type: FieldNumber, Sort: true},
+		"created_at": {Column: "keyset_rows.created_at", Type: FieldTime, Filter: true, Sort: true},
+		"archived":   {Column: "keyset_rows.archived", Type: FieldBool, Filter: true},
+	},
+	Search:      []string{"keyset_rows.title"},
+	DefaultSort: "-id",
+	ID:          "keyset_rows.id",
+}
+
+func parseTestList(t *testing.T, rawQuery string) (ListQuery, error) {
+	t.Helper()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest("GET", "/list?"+rawQuery, nil)
+
+	return ParseList(c, testListSpec)
+}
+
+// whereSQL returns the WHERE clause the filters of list add to a query.
+func whereSQL(t *testing.T, list ListQuery) string {
+	t.Helper()
+
+	sql := dryRunDB(t).ToSQL(func(tx *gorm.DB) *gorm.DB {
+		return tx.Model(&keysetRow{}).Scopes(list.Filter).Find(&[]keysetRow{})
+	})
+
+	_, where, found := strings.Cut(sql, " WHERE ")
+	if !found {
+		return ""
+	}
+	return where
+}
+
+func TestEscapeLike(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"sunset", "sunset"},
+		{"100%", `100\%`},
+		{"snake_case", `snake\_case`},
+		{`back\slash`, `back\\slash`},
+		{`%_\`, `\%\_\\`},
+		{"", ""},
+	}
+
+	for _, tt := range tests {
+		if got := escapeLike(tt.text); got != tt.want {
+			t.Errorf("escapeLike(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestParseListFilters(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		want  string
+	}{
+		{
+			name:  "no filters",
+			query: "",
+			want:  "",
+		},
+		{
+			name:  "equals one of",
+			query: "id=3,4",
+			want:  "keyset_rows.id IN (3,4)",
+		},
+		{
+			name:  "string equals",
+			query: "title=sunset",
+			want:  "keyset_rows.title IN ('sunset')",
+		},
+		{
+			name:  "bool equals",
+			query: "archived=true",
+			want:  "keyset_rows.archived IN (true)",
+		},
+		{
+			name:  "after a date",
+			query: "created_after=2024-01-31",
+			want:  "keyset_rows.created_at > '2024-01-31 00:00:00'",
+		},
+		{
+			name:  "before a time",
+			query: "created_before=2024-01-31T15:04:05Z",
+			want:  "keyset_rows.created_at < '2024-01-31 15:04:05'",
+		},
+		{
+			name:  "between",
+			query: "created_after=2024-01-01&created_before=2024-02-01",
+			want:  "keyset_rows.created_at > '2024-01-01 00:00:00' AND keyset_rows.created_at < '2024-02-01 00:00:00'",
+		},
+		{
+			name:  "search escapes wildcards",
+			query: "q=50%25_off",
+			want:  `(keyset_rows.title ILIKE '%50\%\_off%')`,
+		},
+		{
+			name:  "fields that can't be filtered on are left alone",
+			query: "like_count=3&created_at=2024-01-31&page=2",
+			want:  "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := parseTestList(t, tt.query)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := whereSQL(t, list); got != tt.want {
+				t.Errorf("WHERE %s, want WHERE %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListRejectsInvalidValues(t *testing.T) {
+	tests := []struct {
+		query string
+		want  string
+	}{
+		{"id=3,x", "id must be a number"},
+		{"created_after=yesterday", "created_after must be a date"},
+		{"created_before=31-01-2024", "created_before must be a date"},
+		{"archived=maybe", "archived must be true or false"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := parseTestList(t, tt.query)
+			if err == nil || !strings.HasPrefix(err.Error(), tt.want) {
+				t.Errorf("err = %v, want %q", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseListSort(t *testing.T) {
+	tests := []struct {
+		name    string
+		query   string
+		want    []SortKey
+		wantErr string
+	}{
+		{
+			name:  "default sort",
+			query: "",
+			want:  []SortKey{{Column: "keyset_rows.id", Desc: true}},
+		},
+		{
+			name:  "ascending tie-break",
+			query: "sort=title",
+			want:  []SortKey{{Column: "keyset_rows.title"}, {Column: "keyset_rows.id"}},
+		},
+		{
+			name:  "tie-break follows the last key",
+			query: "sort=title,-created_at",
+			want:  []SortKey{{Column: "keyset_rows.title"}, {Column: "keyset_rows.created_at", Desc: true}, {Column: "keyset_rows.id", Desc: true}},
+		},
+		{
+			name:  "no tie-break when sorted by the ID",
+			query: "sort=-like_count,id",
+			want:  []SortKey{{Column: "keyset_rows.like_count", Desc: true}, {Column: "keyset_rows.id"}},
+		},
+		{
+			name:  "repeated fields count once",
+			query: "sort=-like_count,like_count,%20,",
+			want:  []SortKey{{Column: "keyset_rows.like_count", Desc: true}, {Column: "keyset_rows.id", Desc: true}},
+		},
+		{
+			name:    "unknown field",
+			query:   "sort=password",
+			wantErr: "the list can't be sorted by password",
+		},
+		{
+			name:    "field that can't be sorted on",
+			query:   "sort=title,-archived",
+			wantErr: "the list can't be sorted by archived",
+		},
+		{
+			name:    "column names aren't field names",
+			query:   "sort=keyset_rows.id",
+			wantErr: "the list can't be sorted by keyset_rows.id",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := parseTestList(t, tt.query)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("err = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if !reflect.DeepEqual(list.Sort, tt.want) {
+				t.Errorf("Sort = %+v, want %+v", list.Sort, tt.want)
+			}
+		})
+	}
+}
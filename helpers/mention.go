@@ -0,0 +1,39 @@
+package helpers
+
+import (
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// mentionPattern matches @username when it starts the text or follows a
+// character that cannot be part of a username or email address.
+var mentionPattern = regexp.MustCompile(`(?:^|[^\w@.])(@[A-Za-z0-9_.]+)`)
+
+type MentionToken struct {
+	Username string
+	Offset   int
+	Length   int
+}
+
+// ParseMentions returns every @username reference in text. Offset and Length
+// are counted in characters and cover the leading @.
+func ParseMentions(text string) []MentionToken {
+	tokens := []MentionToken{}
+
+	for _, match := range mentionPattern.FindAllStringSubmatchIndex(text, -1) {
+		start, end := match[2], match[3]
+		handle := strings.TrimRight(text[start:end], ".")
+		if len(handle) < 2 {
+			continue
+		}
+
+		tokens = append(tokens, MentionToken{
+			Username: handle[1:],
+			Offset:   utf8.RuneCountInString(text[:start]),
+			Length:   utf8.RuneCountInString(handle),
+		})
+	}
+
+	return tokens
+}
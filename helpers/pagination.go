@@ -0,0 +1,49 @@
+package helpers
+
+import (
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+const (
+	DefaultPageSize = 20
+	MaxPageSize     = 100
+)
+
+type Page struct {
+	Page  int `json:"page"`
+	Limit int `json:"limit"`
+}
+
+// GetPage reads the page and limit query parameters. Missing or invalid
+// values fall back to the first page of DefaultPageSize items, and limit is
+// capped at MaxPageSize.
+func GetPage(c *gin.Context) Page {
+	page, err := strconv.Atoi(c.Query("page"))
+	if err != nil || page < 1 {
+		page = 1
+	}
+
+	return Page{Page: page, Limit: GetLimit(c)}
+}
+
+// GetLimit reads the limit query parameter, falling back to DefaultPageSize
+// and capped at MaxPageSize.
+func GetLimit(c *gin.Context) int {
+	limit, err := strconv.Atoi(c.Query("limit"))
+	if err != nil || limit < 1 {
+		limit = DefaultPageSize
+	}
+	if limit > MaxPageSize {
+		limit = MaxPageSize
+	}
+
+	return limit
+}
+
+// Paginate limits a query to the rows of the page.
+func (p Page) Paginate(db *gorm.DB) *gorm.DB {
+	return db.Offset((p.Page - 1) * p.Limit).Limit(p.Limit)
+}
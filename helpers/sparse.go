@@ -0,0 +1,182 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"gorm.io/gorm"
+)
+
+// MaxIncludeDepth is how deeply relations can be nested in include, as in
+// include=comments.user.
+const MaxIncludeDepth = 2
+
+// Include is a relation a resource can embed. Preload is the name of the
+// association in GORM, and Scope, when set, limits the rows loaded for it.
+type Include struct {
+	Preload string
+	Scope   func(*gorm.DB) *gorm.DB
+}
+
+// Sparse is the fields and relations a client asked to get for a resource
+// with the fields and include query parameters.
+type Sparse struct {
+	fields   map[string]bool
+	includes []Include
+}
+
+// ParseSparse reads the fields and include query parameters. Fields have to
+// be JSON fields of model and includes keys of the relations the resource
+// allows, each of whose parents has to be allowed too. Relations nested
+// deeper than MaxIncludeDepth are rejected.
+func ParseSparse(c *gin.Context, model interface{}, relations map[string]Include) (Sparse, error) {
+	sparse := Sparse{}
+
+	included := map[string]bool{}
+	for _, name := range splitList(c.Query("include")) {
+		segments := strings.Split(name, ".")
+		if len(segments) > MaxIncludeDepth {
+			return sparse, fmt.Errorf("include can nest at most %d relations", MaxIncludeDepth)
+		}
+
+		// Parents are included with their own scope, as preloading a nested
+		// relation loads its parents unscoped otherwise.
+		for i := range segments {
+			path := strings.Join(segments[:i+1], ".")
+			if _, ok := relations[path]; !ok {
+				return sparse, fmt.Errorf("%s can't be included", path)
+			}
+			included[path] = true
+		}
+	}
+
+	paths := make([]string, 0, len(included))
+	for path := range included {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+	for _, path := range paths {
+		sparse.includes = append(sparse.includes, relations[path])
+	}
+
+	fields := splitList(c.Query("fields"))
+	if len(fields) == 0 {
+		return sparse, nil
+	}
+
+	known := jsonFields(reflect.TypeOf(model))
+	sparse.fields = map[string]bool{}
+	for _, field := range fields {
+		if !known[field] {
+			return sparse, fmt.Errorf("unknown field %s", field)
+		}
+		sparse.fields[field] = true
+	}
+
+	// Included relations are returned whatever the fields.
+	for path := range included {
+		sparse.fields[strings.Split(path, ".")[0]] = true
+	}
+
+	return sparse, nil
+}
+
+// Preload preloads the included relations.
+func (s Sparse) Preload(db *gorm.DB) *gorm.DB {
+	for _, include := range s.includes {
+		if include.Scope != nil {
+			db = db.Preload(include.Preload, include.Scope)
+		} else {
+			db = db.Preload(include.Preload)
+		}
+	}
+
+	return db
+}
+
+// JSON responds with obj cut down to the fields the client asked for. The
+// items of lists, which are objects with a data array, are cut down one by
+// one, while the rest of the list is left as it is.
+func (s Sparse) JSON(c *gin.Context, code int, obj interface{}) {
+	if s.fields == nil {
+		c.JSON(code, obj)
+		return
+	}
+
+	raw, err := json.Marshal(obj)
+	if err != nil {
+		c.JSON(code, obj)
+		return
+	}
+
+	object := map[string]json.RawMessage{}
+	if json.Unmarshal(raw, &object) != nil {
+		c.JSON(code, obj)
+		return
+	}
+
+	items := []map[string]json.RawMessage{}
+	if data, ok := object["data"]; ok && json.Unmarshal(data, &items) == nil {
+		for _, item := range items {
+			s.cut(item)
+		}
+		data, _ = json.Marshal(items)
+		object["data"] = data
+	} else {
+		s.cut(object)
+	}
+
+	c.JSON(code, object)
+}
+
+func (s Sparse) cut(object map[string]json.RawMessage) {
+	for field := range object {
+		if !s.fields[field] {
+			delete(object, field)
+		}
+	}
+}
+
+// jsonFields lists the names of the fields of a struct type in JSON,
+// including the ones of embedded structs.
+func jsonFields(t reflect.Type) map[string]bool {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+
+	fields := map[string]bool{}
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		name := strings.Split(field.Tag.Get("json"), ",")[0]
+		switch {
+		case name == "-" || !field.IsExported():
+			continue
+		case field.Anonymous && name == "":
+			for embedded := range jsonFields(field.Type) {
+				fields[embedded] = true
+			}
+			continue
+		case name == "":
+			name = field.Name
+		}
+		fields[name] = true
+	}
+
+	return fields
+}
+
+func splitList(list string) []string {
+	items := []string{}
+	for _, item := range strings.Split(list, ",") {
+		item = strings.TrimSpace(item)
+		if item != "" {
+			items = append(items, item)
+		}
+	}
+
+	return items
+}
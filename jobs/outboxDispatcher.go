@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/outbox"
+)
+
+// outboxRetention is how long dispatched events are kept in the outbox.
+const outboxRetention = 7 * 24 * time.Hour
+
+// StartOutboxDispatcher sends the events recorded in the outbox to the
+// sinks, and deletes the ones dispatched longer than a week ago every hour.
+func StartOutboxDispatcher(db *gorm.DB, interval time.Duration, sinks ...outbox.Sink) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		purgeTicker := time.NewTicker(time.Hour)
+		for {
+			select {
+			case <-ticker.C:
+				dispatchOutbox(db, sinks)
+			case <-purgeTicker.C:
+				_, err := outbox.Purge(db, time.Now().Add(-outboxRetention))
+				if err != nil {
+					log.Println("error purging outbox :", err)
+				}
+			}
+		}
+	}()
+}
+
+// dispatchOutbox keeps dispatching while there are full batches of events
+// waiting, so a burst of writes doesn't wait several ticks.
+func dispatchOutbox(db *gorm.DB, sinks []outbox.Sink) {
+	for {
+		count, err := outbox.Dispatch(db, sinks)
+		if err != nil {
+			log.Println("error dispatching outbox :", err)
+			return
+		}
+		if count < outbox.BatchSize {
+			return
+		}
+	}
+}
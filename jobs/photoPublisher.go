@@ -0,0 +1,64 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/events"
+	"tesjwt.go/feed"
+	"tesjwt.go/models"
+	"tesjwt.go/outbox"
+)
+
+// StartPhotoPublisher publishes scheduled photos once their publish_at has
+// passed. Due photos are looked up in the database on every run, so posts
+// that fell due while the app was down go out right after it starts again.
+func StartPhotoPublisher(db *gorm.DB, interval time.Duration) {
+	go func() {
+		publishDuePhotos(db)
+
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			publishDuePhotos(db)
+		}
+	}()
+}
+
+func publishDuePhotos(db *gorm.DB) {
+	published := []models.Photo{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&published).Clauses(clause.Returning{}).
+			Where("status = ? AND publish_at <= ?", models.PhotoScheduled, time.Now()).
+			UpdateColumns(map[string]interface{}{
+				"status":       models.PhotoPublished,
+				"published_at": gorm.Expr("publish_at"),
+			}).Error
+		if err != nil {
+			return err
+		}
+
+		for _, photo := range published {
+			err = feed.FanOut(tx, photo)
+			if err != nil {
+				return err
+			}
+
+			err = outbox.Record(tx, events.Event{Type: events.PhotoPublished, ActorID: photo.UserID, Payload: photo})
+			if err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		log.Println("error publishing scheduled photos :", err)
+		return
+	}
+
+	if len(published) > 0 {
+		log.Printf("published %d scheduled photos", len(published))
+	}
+}
@@ -0,0 +1,37 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// StartStoryArchiver archives stories once they have expired. Reads check
+// the expiry time themselves, so stories disappear on time even between
+// runs.
+func StartStoryArchiver(db *gorm.DB, interval time.Duration) {
+	go func() {
+		archiveExpiredStories(db)
+
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			archiveExpiredStories(db)
+		}
+	}()
+}
+
+func archiveExpiredStories(db *gorm.DB) {
+	result := db.Model(&models.Story{}).
+		Where("archived_at IS NULL AND expires_at <= ?", time.Now()).
+		UpdateColumn("archived_at", gorm.Expr("expires_at"))
+	if result.Error != nil {
+		log.Println("error archiving expired stories :", result.Error)
+		return
+	}
+
+	if result.RowsAffected > 0 {
+		log.Printf("archived %d expired stories", result.RowsAffected)
+	}
+}
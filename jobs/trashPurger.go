@@ -0,0 +1,47 @@
+package jobs
+
+import (
+	"log"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/models"
+)
+
+// StartTrashPurger permanently deletes photos, comments and social media that
+// have been in the trash for longer than retention.
+func StartTrashPurger(db *gorm.DB, retention, interval time.Duration) {
+	go func() {
+		purgeTrash(db, retention)
+
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			purgeTrash(db, retention)
+		}
+	}()
+}
+
+func purgeTrash(db *gorm.DB, retention time.Duration) {
+	cutoff := time.Now().Add(-retention)
+
+	for _, model := range []interface{}{&models.Comment{}, &models.SocialMedia{}, &models.Photo{}} {
+		err := db.Unscoped().Where("deleted_at < ?", cutoff).Delete(model).Error
+		if err != nil {
+			log.Println("error purging trash :", err)
+			return
+		}
+	}
+
+	// Mentions and reactions point at photos and comments without a foreign
+	// key, so they are cleaned up here once their target is gone.
+	for _, model := range []interface{}{&models.Mention{}, &models.Reaction{}, &models.ReactionCount{}} {
+		err := db.Unscoped().
+			Where("target_type = ? AND target_id NOT IN (SELECT id FROM photos)", models.TargetPhoto).
+			Or("target_type = ? AND target_id NOT IN (SELECT id FROM comments)", models.TargetComment).
+			Delete(model).Error
+		if err != nil {
+			log.Println("error purging trash :", err)
+			return
+		}
+	}
+}
@@ -0,0 +1,40 @@
+package jobs
+
+import (
+	"log"
+	"net"
+	"net/http"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/helpers"
+	"tesjwt.go/webhooks"
+)
+
+// StartWebhookDispatcher sends queued webhook deliveries, and retries the
+// failed ones once their backoff has passed.
+func StartWebhookDispatcher(db *gorm.DB, interval time.Duration) {
+	dialer := &net.Dialer{
+		Timeout: 5 * time.Second,
+		Control: helpers.DialPublicOnly,
+	}
+	client := &http.Client{
+		Timeout:   10 * time.Second,
+		Transport: &http.Transport{DialContext: dialer.DialContext},
+		// Redirects aren't followed, so a webhook can't bounce the request
+		// to an internal address; a 3xx counts as a failed delivery.
+		CheckRedirect: func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		},
+	}
+
+	go func() {
+		ticker := time.NewTicker(interval)
+		for range ticker.C {
+			err := webhooks.DeliverDue(db, client)
+			if err != nil {
+				log.Println("error delivering webhooks :", err)
+			}
+		}
+	}()
+}
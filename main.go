@@ -3,11 +3,18 @@ package main
 import (
 	"log"
 	"os"
+	"time"
 
 	"github.com/joho/godotenv"
+	"tesjwt.go/controllers"
 	"tesjwt.go/database"
 	_ "tesjwt.go/docs"
+	"tesjwt.go/helpers"
+	"tesjwt.go/jobs"
+	"tesjwt.go/notifications"
+	"tesjwt.go/outbox"
 	"tesjwt.go/router"
+	"tesjwt.go/webhooks"
 )
 
 func main() {
@@ -18,6 +25,13 @@ func main() {
 		}
 	}
 	database.StartDB()
+	notifications.Register(database.GetDB())
+	controllers.RegisterStreamEvents(database.GetDB())
+	jobs.StartPhotoPublisher(database.GetDB(), time.Minute)
+	jobs.StartTrashPurger(database.GetDB(), helpers.TrashRetention(), time.Hour)
+	jobs.StartStoryArchiver(database.GetDB(), time.Minute)
+	jobs.StartOutboxDispatcher(database.GetDB(), time.Second, outbox.Bus{}, webhooks.Sink{DB: database.GetDB()})
+	jobs.StartWebhookDispatcher(database.GetDB(), 10*time.Second)
 	r := router.StartApp()
 	log.Println("starting app...")
 	r.Run(":5000")
@@ -0,0 +1,16 @@
+package middlewares
+
+import "github.com/gin-gonic/gin"
+
+// QueryToken lets clients that can't set request headers, such as browser
+// EventSource and WebSocket clients, pass their JWT as the access_token query
+// parameter. It has to run before Authentication.
+func QueryToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.Query("access_token")
+		if token != "" && c.GetHeader("Authorization") == "" {
+			c.Request.Header.Set("Authorization", "Bearer "+token)
+		}
+		c.Next()
+	}
+}
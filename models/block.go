@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Block is a user blocking another. Blocked users can't see the blocker's
+// photos, comment on them, follow or mention the blocker.
+type Block struct {
+	ID        uint       `gorm:"primarykey" json:"-"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	BlockerID uint       `gorm:"not null;uniqueIndex:idx_blocks_unique" json:"blocker_id"`
+	BlockedID uint       `gorm:"not null;uniqueIndex:idx_blocks_unique;index" json:"blocked_id"`
+	Blocker   *User      `gorm:"foreignKey:BlockerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Blocked   *User      `gorm:"foreignKey:BlockedID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
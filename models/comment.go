@@ -1,16 +1,41 @@
 package models
 
 import (
+	"time"
+
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+// MaxCommentDepth is how deeply replies can be nested. Top-level comments
+// have a depth of 0.
+const MaxCommentDepth = 4
+
+// Comment moderation states. Hidden and held comments are only shown to
+// their author and to the owner of the photo.
+const (
+	CommentVisible = "visible"
+	CommentHidden  = "hidden"
+	CommentHeld    = "held"
+)
+
 type Comment struct {
 	GormModel
-	Message string `json:"message" form:"message" valid:"required~Your message is required"`
-	UserID  uint
-	PhotoID uint
-	User    *User `json:",omitempty"`
+	Message       string `json:"message" form:"message" valid:"required~Your message is required"`
+	UserID        uint
+	PhotoID       uint
+	ParentID      *uint          `gorm:"index" json:"parent_id,omitempty"`
+	Status        string         `gorm:"not null;default:visible;index" json:"status"`
+	Depth         int            `gorm:"not null;default:0" json:"depth"`
+	EditedAt      *time.Time     `json:"edited_at,omitempty"`
+	RevisionCount int            `gorm:"not null;default:0" json:"revision_count"`
+	User          *User          `json:"user,omitempty"`
+	Author        *UserSummary   `gorm:"-" json:"author,omitempty"`
+	Photo         *Photo         `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"photo,omitempty"`
+	Mentions      []Mention      `gorm:"polymorphic:Target" json:"mentions,omitempty"`
+	Replies       []Comment      `gorm:"foreignKey:ParentID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"replies,omitempty"`
+	ReplyCount    int            `gorm:"-" json:"reply_count"`
+	Reactions     map[string]int `gorm:"-" json:"reactions,omitempty"`
 }
 
 func (c *Comment) BeforeCreate(tx *gorm.DB) (err error) {
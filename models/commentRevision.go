@@ -0,0 +1,10 @@
+package models
+
+// CommentRevision keeps a message a comment had before it was edited.
+type CommentRevision struct {
+	GormModel
+	CommentID uint     `gorm:"not null;index" json:"comment_id"`
+	Message   string   `gorm:"not null" json:"message"`
+	EditorID  uint     `gorm:"not null" json:"editor_id"`
+	Comment   *Comment `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
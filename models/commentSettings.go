@@ -0,0 +1,58 @@
+package models
+
+import (
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+)
+
+// Who can comment on a photo.
+const (
+	CommentsEveryone  = "everyone"
+	CommentsFollowers = "followers"
+	CommentsDisabled  = "disabled"
+)
+
+// CommentSettings is how the owner of a photo moderates its comments.
+// Comments containing a keyword of the blocklist are held for review.
+type CommentSettings struct {
+	PhotoID   uint       `gorm:"primaryKey" json:"photo_id"`
+	Policy    string     `gorm:"not null;default:everyone" json:"policy" form:"policy" valid:"in(everyone|followers|disabled)~Policy must be everyone or followers or disabled"`
+	Keywords  string     `gorm:"not null;default:''" json:"-" form:"-"`
+	Blocklist []string   `gorm:"-" json:"blocklist" form:"blocklist"`
+	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	Photo     *Photo     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (s *CommentSettings) BeforeSave(tx *gorm.DB) (err error) {
+	_, errCreate := govalidator.ValidateStruct(s)
+
+	if errCreate != nil {
+		err = errCreate
+		return
+	}
+
+	keywords := []string{}
+	for _, keyword := range s.Blocklist {
+		keyword = strings.ToLower(strings.TrimSpace(keyword))
+		if keyword != "" {
+			keywords = append(keywords, keyword)
+		}
+	}
+
+	s.Blocklist = keywords
+	s.Keywords = strings.Join(keywords, "\n")
+	err = nil
+	return
+}
+
+func (s *CommentSettings) AfterFind(tx *gorm.DB) (err error) {
+	s.Blocklist = []string{}
+	if s.Keywords != "" {
+		s.Blocklist = strings.Split(s.Keywords, "\n")
+	}
+
+	return nil
+}
@@ -0,0 +1,34 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/asaskevich/govalidator"
+)
+
+func TestCommentSettingsValidation(t *testing.T) {
+	tests := []struct {
+		policy  string
+		wantErr string
+	}{
+		{CommentsEveryone, ""},
+		{CommentsFollowers, ""},
+		{CommentsDisabled, ""},
+		{"friends", "Policy must be everyone or followers or disabled"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.policy, func(t *testing.T) {
+			_, err := govalidator.ValidateStruct(CommentSettings{Policy: tt.policy})
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,31 @@
+package models
+
+import "time"
+
+// MaxConversationMembers is how many users, the creator included, can take
+// part in a group conversation.
+const MaxConversationMembers = 10
+
+// Conversation is a private exchange of messages between two users, or
+// between a small group of them.
+type Conversation struct {
+	ID            uint                 `gorm:"primarykey" json:"id"`
+	CreatedAt     *time.Time           `json:"created_at,omitempty"`
+	CreatorID     uint                 `gorm:"not null" json:"creator_id"`
+	IsGroup       bool                 `gorm:"not null;default:false" json:"is_group"`
+	LastMessageAt *time.Time           `gorm:"index" json:"last_message_at,omitempty"`
+	Members       []ConversationMember `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"members,omitempty"`
+	LastMessage   *Message             `gorm:"-" json:"last_message,omitempty"`
+	UnreadCount   int64                `gorm:"-" json:"unread_count"`
+}
+
+// ConversationMember is a user taking part in a conversation. The last
+// message they read is shown to the other members as a read receipt.
+type ConversationMember struct {
+	ConversationID    uint         `gorm:"primaryKey" json:"-"`
+	UserID            uint         `gorm:"primaryKey;index" json:"user_id"`
+	CreatedAt         *time.Time   `json:"joined_at,omitempty"`
+	LastReadMessageID *uint        `json:"last_read_message_id,omitempty"`
+	LastReadAt        *time.Time   `json:"last_read_at,omitempty"`
+	User              *UserSummary `gorm:"-" json:"user,omitempty"`
+}
@@ -0,0 +1,16 @@
+package models
+
+import "time"
+
+// FeedItem is a photo fanned out to the feed of one of its author's
+// followers when it was published.
+type FeedItem struct {
+	ID          uint       `gorm:"primarykey" json:"-"`
+	CreatedAt   *time.Time `json:"created_at,omitempty"`
+	UserID      uint       `gorm:"not null;uniqueIndex:idx_feed_items_unique;index:idx_feed_items_page,priority:1" json:"user_id"`
+	PhotoID     uint       `gorm:"not null;uniqueIndex:idx_feed_items_unique" json:"photo_id"`
+	AuthorID    uint       `gorm:"not null;index" json:"author_id"`
+	PublishedAt time.Time  `gorm:"not null;index:idx_feed_items_page,priority:2,sort:desc" json:"published_at"`
+	User        *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Photo       *Photo     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// Follow states. Following a private account starts out pending until the
+// account owner approves it.
+const (
+	FollowPending  = "pending"
+	FollowAccepted = "accepted"
+)
+
+// Follow is a user following another, or asking to when the followee's
+// account is private.
+type Follow struct {
+	ID         uint       `gorm:"primarykey" json:"-"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	FollowerID uint       `gorm:"not null;uniqueIndex:idx_follows_unique" json:"follower_id"`
+	FolloweeID uint       `gorm:"not null;uniqueIndex:idx_follows_unique;index" json:"followee_id"`
+	Status     string     `gorm:"not null;default:accepted;index" json:"status"`
+	Follower   *User      `gorm:"foreignKey:FollowerID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Followee   *User      `gorm:"foreignKey:FolloweeID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
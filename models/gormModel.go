@@ -1,9 +1,17 @@
 package models
 
-import "time"
+import (
+	"time"
 
+	"gorm.io/gorm"
+)
+
+// GormModel holds the ID and timestamps most models share. Its DeletedAt
+// makes deletes soft, which is what lets the trash restore photos, comments
+// and social media; records removed for good are deleted with Unscoped.
 type GormModel struct {
-	ID        uint       `gorm:"primarykey" json:"id"`
-	CreatedAt *time.Time `json:"created_at,omitempty"`
-	UpdatedAt *time.Time `json:"updated_at,omitempty"`
+	ID        uint           `gorm:"primarykey" json:"id"`
+	CreatedAt *time.Time     `json:"created_at,omitempty"`
+	UpdatedAt *time.Time     `json:"updated_at,omitempty"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
 }
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+// Like is a user liking a photo. A user can like each photo once.
+type Like struct {
+	ID        uint       `gorm:"primarykey" json:"-"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	UserID    uint       `gorm:"not null;uniqueIndex:idx_likes_unique" json:"user_id"`
+	PhotoID   uint       `gorm:"not null;uniqueIndex:idx_likes_unique;index" json:"photo_id"`
+	User      *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Photo     *Photo     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,18 @@
+package models
+
+// Target types used by records that can point at either a photo or a
+// comment. They match the table names GORM uses for polymorphic associations.
+const (
+	TargetPhoto   = "photos"
+	TargetComment = "comments"
+)
+
+type Mention struct {
+	GormModel
+	TargetID   uint   `gorm:"not null;index:idx_mentions_target" json:"-"`
+	TargetType string `gorm:"not null;index:idx_mentions_target" json:"-"`
+	UserID     uint   `gorm:"not null;index" json:"user_id"`
+	Username   string `gorm:"not null" json:"username"`
+	Offset     int    `gorm:"not null" json:"offset"`
+	Length     int    `gorm:"not null" json:"length"`
+}
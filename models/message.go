@@ -0,0 +1,33 @@
+package models
+
+import (
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+)
+
+// Message is sent to a conversation. It may share a photo by reference, in
+// which case the photo is only shown to members who can see it themselves.
+type Message struct {
+	ID             uint          `gorm:"primarykey" json:"id"`
+	CreatedAt      *time.Time    `json:"created_at,omitempty"`
+	ConversationID uint          `gorm:"not null;index" json:"conversation_id"`
+	SenderID       uint          `gorm:"not null" json:"sender_id"`
+	Body           string        `json:"body" form:"body" valid:"maxstringlength(2000)~Message can't be longer than 2000 characters"`
+	PhotoID        *uint         `json:"photo_id,omitempty" form:"photo_id"`
+	Photo          *Photo        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:SET NULL" json:"photo,omitempty"`
+	Conversation   *Conversation `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (m *Message) BeforeCreate(tx *gorm.DB) (err error) {
+	_, errCreate := govalidator.ValidateStruct(m)
+
+	if errCreate != nil {
+		err = errCreate
+		return
+	}
+
+	err = nil
+	return
+}
@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// Mute is a user muting another. Photos of muted users are left out of the
+// muter's feeds, but nothing changes for the muted user.
+type Mute struct {
+	ID        uint       `gorm:"primarykey" json:"-"`
+	CreatedAt *time.Time `json:"created_at,omitempty"`
+	MuterID   uint       `gorm:"not null;uniqueIndex:idx_mutes_unique" json:"muter_id"`
+	MutedID   uint       `gorm:"not null;uniqueIndex:idx_mutes_unique" json:"muted_id"`
+	Muter     *User      `gorm:"foreignKey:MuterID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Muted     *User      `gorm:"foreignKey:MutedID;constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
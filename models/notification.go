@@ -0,0 +1,50 @@
+package models
+
+import "time"
+
+// Notification types.
+const (
+	NotificationComment       = "comment"
+	NotificationReply         = "reply"
+	NotificationLike          = "like"
+	NotificationFollow        = "follow"
+	NotificationFollowRequest = "follow_request"
+	NotificationMention       = "mention"
+)
+
+// NotificationTypes lists every notification type, in the order they are
+// shown in the preferences.
+var NotificationTypes = []string{
+	NotificationComment,
+	NotificationReply,
+	NotificationLike,
+	NotificationFollow,
+	NotificationFollowRequest,
+	NotificationMention,
+}
+
+// Notification tells a user that someone interacted with them or their
+// photos.
+type Notification struct {
+	ID        uint         `gorm:"primarykey" json:"id"`
+	CreatedAt *time.Time   `json:"created_at,omitempty"`
+	UserID    uint         `gorm:"not null;index:idx_notifications_user,priority:1" json:"user_id"`
+	ActorID   uint         `gorm:"not null" json:"actor_id"`
+	Type      string       `gorm:"not null" json:"type"`
+	PhotoID   *uint        `json:"photo_id,omitempty"`
+	CommentID *uint        `json:"comment_id,omitempty"`
+	ReadAt    *time.Time   `gorm:"index:idx_notifications_user,priority:2" json:"read_at"`
+	Actor     *UserSummary `gorm:"-" json:"actor,omitempty"`
+	User      *User        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Photo     *Photo       `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Comment   *Comment     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// NotificationPreference turns one type of notification on or off for a
+// user. Types without a preference are on.
+type NotificationPreference struct {
+	UserID  uint   `gorm:"primaryKey" json:"-"`
+	Type    string `gorm:"primaryKey" json:"type"`
+	Enabled bool   `gorm:"not null" json:"enabled"`
+	User    *User  `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
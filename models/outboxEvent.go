@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// OutboxEvent is a domain event recorded in the same transaction as the
+// write it is about, so it exists if and only if the write was committed.
+// The outbox dispatcher hands it to every sink and then marks it dispatched.
+type OutboxEvent struct {
+	ID            uint       `gorm:"primarykey" json:"id"`
+	CreatedAt     *time.Time `json:"created_at,omitempty"`
+	Type          string     `gorm:"not null" json:"type"`
+	ActorID       uint       `gorm:"not null" json:"actor_id"`
+	Payload       string     `gorm:"not null" json:"payload"`
+	OccurredAt    time.Time  `gorm:"not null" json:"occurred_at"`
+	Sinks         string     `gorm:"not null;default:''" json:"-"`
+	Attempts      int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"not null" json:"next_attempt_at"`
+	LastError     string     `json:"last_error,omitempty"`
+	DispatchedAt  *time.Time `gorm:"index:idx_outbox_events_pending,where:dispatched_at IS NULL" json:"dispatched_at,omitempty"`
+}
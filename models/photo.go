@@ -1,17 +1,46 @@
 package models
 
 import (
+	"time"
+
 	"github.com/asaskevich/govalidator"
 	"gorm.io/gorm"
 )
 
+// Photo visibility levels. Unlisted photos are left out of listings but can
+// still be opened by anyone who knows their ID.
+const (
+	VisibilityPublic    = "public"
+	VisibilityFollowers = "followers"
+	VisibilityPrivate   = "private"
+	VisibilityUnlisted  = "unlisted"
+)
+
+// Photo publishing states. Scheduled photos are published by a background
+// job once PublishAt has passed.
+const (
+	PhotoDraft     = "draft"
+	PhotoScheduled = "scheduled"
+	PhotoPublished = "published"
+)
+
 type Photo struct {
 	GormModel
-	Title    string `json:"title" form:"title" valid:"required~Title is required"`
-	Caption  string `json:"caption" form:"caption" valid:"required~Caption is required"`
-	PhotoUrl string `json:"photo_url" form:"photo_url" valid:"required~Your Photo Url is required"`
-	UserID   uint
-	User     *User `json:",omitempty"`
+	Title       string     `json:"title" form:"title" valid:"required~Title is required"`
+	Caption     string     `json:"caption" form:"caption" valid:"required~Caption is required"`
+	PhotoUrl    string     `json:"photo_url" form:"photo_url" valid:"required~Your Photo Url is required"`
+	Visibility  string     `gorm:"not null;default:public;index" json:"visibility" form:"visibility" valid:"in(public|followers|private|unlisted)~Visibility must be public or followers or private or unlisted"`
+	Status      string     `gorm:"not null;default:published;index" json:"status" form:"status" valid:"in(draft|scheduled|published)~Status must be draft or scheduled or published"`
+	PublishAt   *time.Time `gorm:"index" json:"publish_at,omitempty" form:"publish_at"`
+	PublishedAt *time.Time `json:"published_at,omitempty"`
+	LikeCount   int        `gorm:"not null;default:0" json:"like_count"`
+	LikedByMe   bool       `gorm:"-" json:"liked_by_me"`
+	FannedOut   bool       `gorm:"not null;default:false" json:"-" form:"-"`
+	UserID      uint
+	User        *User          `json:"user,omitempty"`
+	Comments    []Comment      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"comments,omitempty"`
+	Mentions    []Mention      `gorm:"polymorphic:Target" json:"mentions,omitempty"`
+	Reactions   map[string]int `gorm:"-" json:"reactions,omitempty"`
 }
 
 func (p *Photo) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,49 @@
+package models
+
+import (
+	"testing"
+
+	"github.com/asaskevich/govalidator"
+)
+
+func TestPhotoValidation(t *testing.T) {
+	tests := []struct {
+		name    string
+		photo   Photo
+		wantErr string
+	}{
+		{
+			name:  "well-formed",
+			photo: Photo{Title: "Sunset", Caption: "At the beach", PhotoUrl: "https://example.com/sunset.jpg", Visibility: VisibilityPublic, Status: PhotoPublished},
+		},
+		{
+			name:  "every visibility",
+			photo: Photo{Title: "Sunset", Caption: "At the beach", PhotoUrl: "https://example.com/sunset.jpg", Visibility: VisibilityUnlisted, Status: PhotoDraft},
+		},
+		{
+			name:    "unknown visibility",
+			photo:   Photo{Title: "Sunset", Caption: "At the beach", PhotoUrl: "https://example.com/sunset.jpg", Visibility: "friends", Status: PhotoPublished},
+			wantErr: "Visibility must be public or followers or private or unlisted",
+		},
+		{
+			name:    "unknown status",
+			photo:   Photo{Title: "Sunset", Caption: "At the beach", PhotoUrl: "https://example.com/sunset.jpg", Visibility: VisibilityPublic, Status: "archived"},
+			wantErr: "Status must be draft or scheduled or published",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := govalidator.ValidateStruct(tt.photo)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("err = %v, want nil", err)
+				}
+				return
+			}
+			if err == nil || err.Error() != tt.wantErr {
+				t.Errorf("err = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,24 @@
+package models
+
+import "time"
+
+// Reaction is an emoji a user put on a photo or a comment. Every user can use
+// each emoji once per target.
+type Reaction struct {
+	ID         uint       `gorm:"primarykey" json:"-"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_reactions_unique" json:"user_id"`
+	TargetType string     `gorm:"not null;uniqueIndex:idx_reactions_unique" json:"target_type"`
+	TargetID   uint       `gorm:"not null;uniqueIndex:idx_reactions_unique" json:"target_id"`
+	Emoji      string     `gorm:"not null;uniqueIndex:idx_reactions_unique" json:"emoji"`
+	User       *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// ReactionCount is how often an emoji was used on a target. It is kept up to
+// date as reactions are added and removed so reads don't have to count them.
+type ReactionCount struct {
+	TargetType string `gorm:"primaryKey" json:"-"`
+	TargetID   uint   `gorm:"primaryKey" json:"-"`
+	Emoji      string `gorm:"primaryKey" json:"emoji"`
+	Count      int    `gorm:"not null;default:0" json:"count"`
+}
@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+// SavedPhoto is a photo a user saved for later, optionally filed under one
+// of their named collections. Saved photos are private to the user who saved
+// them.
+type SavedPhoto struct {
+	ID         uint       `gorm:"primarykey" json:"-"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UserID     uint       `gorm:"not null;uniqueIndex:idx_saved_photos_unique;index:idx_saved_photos_collection,priority:1" json:"user_id"`
+	PhotoID    uint       `gorm:"not null;uniqueIndex:idx_saved_photos_unique;index" json:"photo_id"`
+	Collection string     `gorm:"not null;index:idx_saved_photos_collection,priority:2" json:"collection"`
+	User       *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Photo      *Photo     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// SavedCollection is one of a user's collections of saved photos.
+type SavedCollection struct {
+	Name  string `json:"name"`
+	Count int64  `json:"count"`
+}
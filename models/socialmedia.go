@@ -10,7 +10,7 @@ type SocialMedia struct {
 	Name           string `json:"name" form:"name" valid:"required~Your name is required"`
 	SocialMediaUrl string `json:"social_media_url" form:"social_media_url" valid:"required~Your Social Media Url is required"`
 	UserID         uint
-	User           *User `json:",omitempty"`
+	User           *User `json:"user,omitempty"`
 }
 
 func (s *SocialMedia) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,55 @@
+package models
+
+import (
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+)
+
+// StoryLifetime is how long a story is shown before it is archived.
+const StoryLifetime = 24 * time.Hour
+
+// Story is a photo shared for a day. Once it expires, a background job
+// archives it, after which only its owner can see it.
+type Story struct {
+	ID         uint         `gorm:"primarykey" json:"id"`
+	CreatedAt  *time.Time   `json:"created_at,omitempty"`
+	UserID     uint         `gorm:"not null;index" json:"user_id"`
+	MediaUrl   string       `gorm:"not null" json:"media_url" form:"media_url" valid:"required~Your Media Url is required"`
+	Caption    string       `json:"caption" form:"caption"`
+	ExpiresAt  time.Time    `gorm:"not null;index" json:"expires_at"`
+	ArchivedAt *time.Time   `gorm:"index" json:"archived_at,omitempty"`
+	ViewCount  int          `gorm:"not null;default:0" json:"view_count"`
+	ViewedByMe bool         `gorm:"-" json:"viewed_by_me"`
+	User       *User        `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+	Author     *UserSummary `gorm:"-" json:"author,omitempty"`
+}
+
+// StoryView records that a user watched a story.
+type StoryView struct {
+	StoryID   uint       `gorm:"primaryKey" json:"-"`
+	ViewerID  uint       `gorm:"primaryKey" json:"viewer_id"`
+	CreatedAt *time.Time `json:"viewed_at,omitempty"`
+	Story     *Story     `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+// StoryGroup is the active stories of one user, oldest first.
+type StoryGroup struct {
+	User       *UserSummary `json:"user"`
+	Stories    []Story      `json:"stories"`
+	HasUnseen  bool         `json:"has_unseen"`
+	LastPosted time.Time    `json:"last_posted"`
+}
+
+func (s *Story) BeforeCreate(tx *gorm.DB) (err error) {
+	_, errCreate := govalidator.ValidateStruct(s)
+
+	if errCreate != nil {
+		err = errCreate
+		return
+	}
+
+	err = nil
+	return
+}
@@ -6,12 +6,42 @@ import (
 	"tesjwt.go/helpers"
 )
 
+// User roles. Moderators and admins can look into other users' content,
+// such as the edit history of their comments.
+const (
+	RoleUser      = "user"
+	RoleModerator = "moderator"
+	RoleAdmin     = "admin"
+)
+
 type User struct {
 	GormModel
-	Username string `gorm:"not null" json:"username" form:"username" valid:"required~Your username is required"`
-	Email    string `gorm:"not null" json:"email" form:"email" valid:"required~Your email is required, email~Invalid email format"`
-	Age      uint   `gorm:"not null" json:"age" form:"age" valid:"required~Your age is required"`
-	Password string `gorm:"not null" json:"password" form:"password" valid:"required~Your password is required,minstringlength(6)~Password has to have minimum length of 6 characters"`
+	Username       string `gorm:"not null" json:"username" form:"username" valid:"required~Your username is required"`
+	Email          string `gorm:"not null" json:"email,omitempty" form:"email" valid:"required~Your email is required, email~Invalid email format"`
+	Age            uint   `gorm:"not null" json:"age,omitempty" form:"age" valid:"required~Your age is required"`
+	Password       string `gorm:"not null" json:"password,omitempty" form:"password" valid:"required~Your password is required,minstringlength(6)~Password has to have minimum length of 6 characters"`
+	Role           string `gorm:"not null;default:user" json:"-" form:"-"`
+	IsPrivate      bool   `gorm:"not null;default:false" json:"is_private" form:"is_private"`
+	FollowerCount  int    `gorm:"not null;default:0" json:"follower_count" form:"-"`
+	FollowingCount int    `gorm:"not null;default:0" json:"following_count" form:"-"`
+}
+
+// UserSummary is the part of a user that is safe to embed in other
+// resources.
+type UserSummary struct {
+	ID       uint   `json:"id"`
+	Username string `json:"username"`
+}
+
+// PublicUsers loads only the columns of users that are shown on their
+// profile, for users embedded in other resources. The email, age and
+// password are left empty, which keeps them out of the JSON.
+func PublicUsers(db *gorm.DB) *gorm.DB {
+	return db.Select("id", "username", "is_private", "follower_count", "following_count")
+}
+
+func (u *User) IsModerator() bool {
+	return u.Role == RoleModerator || u.Role == RoleAdmin
 }
 
 func (u *User) BeforeCreate(tx *gorm.DB) (err error) {
@@ -0,0 +1,111 @@
+package models
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/asaskevich/govalidator"
+	"gorm.io/gorm"
+	"tesjwt.go/helpers"
+)
+
+// WebhookEventTypes lists the events webhooks can subscribe to.
+var WebhookEventTypes = []string{
+	"photo.created",
+	"photo.updated",
+	"photo.published",
+	"photo.deleted",
+	"comment.created",
+	"comment.updated",
+	"comment.deleted",
+	"photo.liked",
+	"photo.unliked",
+	"social_media.created",
+	"social_media.updated",
+	"social_media.deleted",
+	"story.created",
+	"user.followed",
+	"user.unfollowed",
+	"user.registered",
+}
+
+// Webhook is an endpoint that gets events POSTed to it as they happen.
+// Webhooks of regular users get the events that involve the user, while the
+// ones of admins get every event.
+type Webhook struct {
+	ID         uint       `gorm:"primarykey" json:"id"`
+	CreatedAt  *time.Time `json:"created_at,omitempty"`
+	UpdatedAt  *time.Time `json:"updated_at,omitempty"`
+	UserID     uint       `gorm:"not null;index" json:"user_id"`
+	URL        string     `gorm:"not null" json:"url" form:"url" valid:"required~URL is required,requrl~URL must be a valid URL,matches(^https?://)~URL must use http or https"`
+	Secret     string     `gorm:"not null" json:"secret,omitempty" form:"-"`
+	Events     string     `gorm:"not null" json:"-" form:"-"`
+	EventTypes []string   `gorm:"-" json:"event_types" form:"event_types"`
+	Active     bool       `gorm:"not null;default:true" json:"active"`
+	User       *User      `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
+
+func (w *Webhook) BeforeSave(tx *gorm.DB) (err error) {
+	_, errCreate := govalidator.ValidateStruct(w)
+
+	if errCreate != nil {
+		err = errCreate
+		return
+	}
+
+	if errURL := helpers.CheckPublicURL(w.URL); errURL != nil {
+		err = errors.New("URL " + errURL.Error())
+		return
+	}
+
+	if len(w.EventTypes) == 0 {
+		err = errors.New("Subscribe to at least one event type")
+		return
+	}
+	for _, eventType := range w.EventTypes {
+		if !govalidator.IsIn(eventType, WebhookEventTypes...) {
+			err = errors.New("Unknown event type " + eventType)
+			return
+		}
+	}
+
+	w.Events = strings.Join(w.EventTypes, "\n")
+	err = nil
+	return
+}
+
+func (w *Webhook) AfterFind(tx *gorm.DB) (err error) {
+	w.EventTypes = []string{}
+	if w.Events != "" {
+		w.EventTypes = strings.Split(w.Events, "\n")
+	}
+
+	return nil
+}
+
+// Webhook delivery states. Deliveries that keep failing end up dead and are
+// only sent again when the owner retries them.
+const (
+	DeliveryPending   = "pending"
+	DeliveryDelivered = "delivered"
+	DeliveryDead      = "dead"
+)
+
+// WebhookDelivery is one event sent, or to be sent, to a webhook, and the
+// outcome of the last attempt.
+type WebhookDelivery struct {
+	ID             uint       `gorm:"primarykey" json:"id"`
+	CreatedAt      *time.Time `json:"created_at,omitempty"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty"`
+	WebhookID      uint       `gorm:"not null;index" json:"webhook_id"`
+	EventType      string     `gorm:"not null" json:"event_type"`
+	Payload        string     `gorm:"not null" json:"payload"`
+	Status         string     `gorm:"not null;default:pending;index:idx_webhook_deliveries_due,priority:1" json:"status"`
+	Attempts       int        `gorm:"not null;default:0" json:"attempts"`
+	NextAttemptAt  *time.Time `gorm:"index:idx_webhook_deliveries_due,priority:2" json:"next_attempt_at,omitempty"`
+	LastStatusCode int        `json:"last_status_code,omitempty"`
+	LastError      string     `json:"last_error,omitempty"`
+	DeliveredAt    *time.Time `json:"delivered_at,omitempty"`
+	Webhook        *Webhook   `gorm:"constraint:OnUpdate:CASCADE,OnDelete:CASCADE" json:"-"`
+}
@@ -0,0 +1,198 @@
+package notifications
+
+import (
+	"log"
+
+	"gorm.io/gorm"
+	"tesjwt.go/events"
+	"tesjwt.go/models"
+)
+
+// silencedSQL counts the reasons not to notify a user about something
+// another user did: the user turned this type of notification off, or muted
+// or blocked the other user.
+const silencedSQL = `SELECT (SELECT COUNT(*) FROM notification_preferences WHERE user_id = ? AND type = ? AND NOT enabled)
+	+ (SELECT COUNT(*) FROM mutes WHERE muter_id = ? AND muted_id = ?)
+	+ (SELECT COUNT(*) FROM blocks WHERE blocker_id = ? AND blocked_id = ?)`
+
+// Register subscribes the notification center to the events it turns into
+// notifications.
+func Register(db *gorm.DB) {
+	subscribe(db, events.CommentCreated, onCommentCreated)
+	subscribe(db, events.PhotoPublished, onPhotoPublished)
+	subscribe(db, events.PhotoLiked, onPhotoLiked)
+	subscribe(db, events.UserFollowed, onUserFollowed)
+}
+
+func subscribe(db *gorm.DB, eventType string, handle func(db *gorm.DB, event events.Event) error) {
+	events.Subscribe(eventType, func(event events.Event) {
+		err := handle(db, event)
+		if err != nil {
+			log.Printf("error creating notifications for %s event : %v", event.Type, err)
+		}
+	})
+}
+
+// onCommentCreated notifies the author of the comment replied to, the owner
+// of the photo and the mentioned users, each of them once. Held comments
+// don't notify anyone until they are reviewed.
+func onCommentCreated(db *gorm.DB, event events.Event) error {
+	comment := event.Payload.(models.Comment)
+	if comment.Status == models.CommentHeld {
+		return nil
+	}
+
+	batch := newBatch(event.ActorID)
+	if comment.ParentID != nil {
+		parent := models.Comment{}
+		err := db.Select("id", "user_id").First(&parent, *comment.ParentID).Error
+		if err != nil {
+			return err
+		}
+		batch.add(parent.UserID, models.NotificationReply, &comment.PhotoID, &comment.ID)
+	}
+
+	photo := models.Photo{}
+	err := db.Select("id", "user_id").First(&photo, comment.PhotoID).Error
+	if err != nil {
+		return err
+	}
+	batch.add(photo.UserID, models.NotificationComment, &comment.PhotoID, &comment.ID)
+
+	err = batch.addMentions(db, models.TargetComment, comment.ID, &comment.PhotoID, &comment.ID)
+	if err != nil {
+		return err
+	}
+
+	return batch.store(db)
+}
+
+// onPhotoPublished notifies the users mentioned in the caption of a photo.
+func onPhotoPublished(db *gorm.DB, event events.Event) error {
+	photo := event.Payload.(models.Photo)
+
+	batch := newBatch(event.ActorID)
+	err := batch.addMentions(db, models.TargetPhoto, photo.ID, &photo.ID, nil)
+	if err != nil {
+		return err
+	}
+
+	return batch.store(db)
+}
+
+// onPhotoLiked notifies the owner of the photo.
+func onPhotoLiked(db *gorm.DB, event events.Event) error {
+	like := event.Payload.(models.Like)
+
+	photo := models.Photo{}
+	err := db.Select("id", "user_id").First(&photo, like.PhotoID).Error
+	if err != nil {
+		return err
+	}
+
+	batch := newBatch(event.ActorID)
+	batch.add(photo.UserID, models.NotificationLike, &photo.ID, nil)
+	return batch.store(db)
+}
+
+// onUserFollowed notifies the followed user of the new follower or follow
+// request.
+func onUserFollowed(db *gorm.DB, event events.Event) error {
+	follow := event.Payload.(models.Follow)
+
+	notificationType := models.NotificationFollow
+	if follow.Status == models.FollowPending {
+		notificationType = models.NotificationFollowRequest
+	}
+
+	batch := newBatch(event.ActorID)
+	batch.add(follow.FolloweeID, notificationType, nil, nil)
+	return batch.store(db)
+}
+
+// batch collects the notifications caused by one event. Every user gets at
+// most one of them, and the actor never gets any.
+type batch struct {
+	actorID       uint
+	notified      map[uint]bool
+	notifications []models.Notification
+}
+
+func newBatch(actorID uint) *batch {
+	return &batch{
+		actorID:  actorID,
+		notified: map[uint]bool{actorID: true},
+	}
+}
+
+func (b *batch) add(userID uint, notificationType string, photoID, commentID *uint) {
+	if b.notified[userID] {
+		return
+	}
+
+	b.notified[userID] = true
+	b.notifications = append(b.notifications, models.Notification{
+		UserID:    userID,
+		ActorID:   b.actorID,
+		Type:      notificationType,
+		PhotoID:   photoID,
+		CommentID: commentID,
+	})
+}
+
+// addMentions adds a mention notification for every user mentioned in the
+// photo or comment.
+func (b *batch) addMentions(db *gorm.DB, targetType string, targetID uint, photoID, commentID *uint) error {
+	userIDs := []uint{}
+	err := db.Model(&models.Mention{}).Where("target_type = ? AND target_id = ?", targetType, targetID).Order("id").Pluck("user_id", &userIDs).Error
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		b.add(userID, models.NotificationMention, photoID, commentID)
+	}
+
+	return nil
+}
+
+// store saves the notifications of the batch, leaving out the ones their
+// user turned off and the ones about users they muted or blocked. Likes and
+// follows are only notified once, so liking a photo again after unliking it
+// doesn't notify its owner twice.
+func (b *batch) store(db *gorm.DB) error {
+	for _, notification := range b.notifications {
+		var silenced int64
+		err := db.Raw(silencedSQL, notification.UserID, notification.Type, notification.UserID, b.actorID, notification.UserID, b.actorID).Scan(&silenced).Error
+		if err != nil {
+			return err
+		}
+		if silenced > 0 {
+			continue
+		}
+
+		if notification.Type == models.NotificationLike || notification.Type == models.NotificationFollow || notification.Type == models.NotificationFollowRequest {
+			var sent int64
+			query := db.Model(&models.Notification{}).Where("user_id = ? AND actor_id = ? AND type = ?", notification.UserID, b.actorID, notification.Type)
+			if notification.PhotoID != nil {
+				query = query.Where("photo_id = ?", *notification.PhotoID)
+			}
+			err = query.Count(&sent).Error
+			if err != nil {
+				return err
+			}
+			if sent > 0 {
+				continue
+			}
+		}
+
+		err = db.Create(&notification).Error
+		if err != nil {
+			return err
+		}
+
+		events.Publish(events.Event{Type: events.NotificationCreated, ActorID: b.actorID, Payload: notification})
+	}
+
+	return nil
+}
@@ -0,0 +1,133 @@
+package outbox
+
+import (
+	"log"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/models"
+)
+
+const (
+	// MaxAttempts is how many times an event is dispatched before the
+	// dispatcher gives up on the sinks that keep failing. Such events stay
+	// in the outbox undispatched, with the last error.
+	MaxAttempts = 12
+	// BaseBackoff is how long the first retry waits. Every further retry
+	// waits twice as long as the one before, up to MaxBackoff.
+	BaseBackoff = time.Second
+	MaxBackoff  = 10 * time.Minute
+
+	// BatchSize is how many events Dispatch handles at most.
+	BatchSize = 100
+
+	// claimTimeout is how long a claimed event is left to the instance that
+	// claimed it. Events of an instance that stopped halfway are picked up
+	// again once it has passed.
+	claimTimeout = time.Minute
+)
+
+// Dispatch sends the pending events to the sinks, oldest first. Events are
+// claimed with SKIP LOCKED and leased for claimTimeout, so several instances
+// of the app can run it at once, and they are sent after the claim is
+// committed so slow sinks don't hold locks. It reports how many events it
+// handled.
+func Dispatch(db *gorm.DB, sinks []Sink) (int, error) {
+	now := time.Now()
+	pending := []models.OutboxEvent{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("dispatched_at IS NULL AND attempts < ? AND next_attempt_at <= ?", MaxAttempts, now).
+			Order("id").Limit(BatchSize).Find(&pending).Error
+		if err != nil || len(pending) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(pending))
+		for _, record := range pending {
+			ids = append(ids, record.ID)
+		}
+
+		return tx.Model(&models.OutboxEvent{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", now.Add(claimTimeout)).Error
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	for _, record := range pending {
+		err = db.Model(&record).UpdateColumns(dispatch(record, sinks)).Error
+		if err != nil {
+			log.Println("error recording outbox dispatch :", err)
+		}
+	}
+
+	return len(pending), nil
+}
+
+// dispatch sends an event to the sinks that haven't got it yet and returns
+// the columns to update with the outcome.
+func dispatch(record models.OutboxEvent, sinks []Sink) map[string]interface{} {
+	now := time.Now()
+	attempts := record.Attempts + 1
+
+	done := map[string]bool{}
+	for _, name := range strings.Fields(record.Sinks) {
+		done[name] = true
+	}
+
+	event, err := decode(record)
+	if err == nil {
+		for _, sink := range sinks {
+			if done[sink.Name()] {
+				continue
+			}
+
+			sendErr := sink.Send(event)
+			if sendErr != nil {
+				log.Printf("error sending %s event %d to %s : %v", record.Type, record.ID, sink.Name(), sendErr)
+				err = sendErr
+				continue
+			}
+			done[sink.Name()] = true
+		}
+	}
+
+	names := make([]string, 0, len(done))
+	for name := range done {
+		names = append(names, name)
+	}
+
+	columns := map[string]interface{}{
+		"attempts": attempts,
+		"sinks":    strings.Join(names, " "),
+	}
+	if err != nil {
+		columns["last_error"] = err.Error()
+		columns["next_attempt_at"] = now.Add(backoff(attempts))
+		return columns
+	}
+
+	columns["last_error"] = ""
+	columns["dispatched_at"] = now
+	return columns
+}
+
+func backoff(attempts int) time.Duration {
+	backoff := BaseBackoff
+	for i := 1; i < attempts && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+
+	return backoff
+}
+
+// Purge deletes the events that were dispatched before the given time.
+func Purge(db *gorm.DB, before time.Time) (int64, error) {
+	result := db.Where("dispatched_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,102 @@
+package outbox
+
+import (
+	"encoding/json"
+	"reflect"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/events"
+	"tesjwt.go/models"
+)
+
+// payloadTypes are the types of the payloads of the events, so sinks get
+// back the same kind of value that was recorded.
+var payloadTypes = map[string]reflect.Type{
+	events.CommentCreated:         reflect.TypeOf(models.Comment{}),
+	events.CommentUpdated:         reflect.TypeOf(models.Comment{}),
+	events.CommentDeleted:         reflect.TypeOf(models.Comment{}),
+	events.CommentRestored:        reflect.TypeOf(models.Comment{}),
+	events.CommentModerated:       reflect.TypeOf(models.Comment{}),
+	events.CommentSettingsUpdated: reflect.TypeOf(models.CommentSettings{}),
+	events.PhotoCreated:           reflect.TypeOf(models.Photo{}),
+	events.PhotoUpdated:           reflect.TypeOf(models.Photo{}),
+	events.PhotoPublished:         reflect.TypeOf(models.Photo{}),
+	events.PhotoDeleted:           reflect.TypeOf(models.Photo{}),
+	events.PhotoRestored:          reflect.TypeOf(models.Photo{}),
+	events.PhotoLiked:             reflect.TypeOf(models.Like{}),
+	events.PhotoUnliked:           reflect.TypeOf(models.Like{}),
+	events.PhotoSaved:             reflect.TypeOf(models.SavedPhoto{}),
+	events.PhotoUnsaved:           reflect.TypeOf(models.SavedPhoto{}),
+	events.ReactionAdded:          reflect.TypeOf(models.Reaction{}),
+	events.ReactionRemoved:        reflect.TypeOf(models.Reaction{}),
+	events.SocialMediaCreated:     reflect.TypeOf(models.SocialMedia{}),
+	events.SocialMediaUpdated:     reflect.TypeOf(models.SocialMedia{}),
+	events.SocialMediaDeleted:     reflect.TypeOf(models.SocialMedia{}),
+	events.SocialMediaRestored:    reflect.TypeOf(models.SocialMedia{}),
+	events.StoryCreated:           reflect.TypeOf(models.Story{}),
+	events.UserFollowed:           reflect.TypeOf(models.Follow{}),
+	events.UserUnfollowed:         reflect.TypeOf(models.Follow{}),
+	events.FollowRequestApproved:  reflect.TypeOf(models.Follow{}),
+	events.FollowRequestRejected:  reflect.TypeOf(models.Follow{}),
+	events.UserBlocked:            reflect.TypeOf(models.Block{}),
+	events.UserUnblocked:          reflect.TypeOf(models.Block{}),
+	events.UserMuted:              reflect.TypeOf(models.Mute{}),
+	events.UserUnmuted:            reflect.TypeOf(models.Mute{}),
+	events.UserRegistered:         reflect.TypeOf(models.UserSummary{}),
+	events.MessageCreated:         reflect.TypeOf(models.Message{}),
+	events.ConversationCreated:    reflect.TypeOf(models.Conversation{}),
+	events.ConversationRead:       reflect.TypeOf(models.ConversationMember{}),
+	events.WebhookCreated:         reflect.TypeOf(models.Webhook{}),
+	events.WebhookUpdated:         reflect.TypeOf(models.Webhook{}),
+	events.WebhookDeleted:         reflect.TypeOf(models.Webhook{}),
+}
+
+// Record stores an event in the outbox. It has to be called with the
+// transaction of the write the event is about, so the event is dropped
+// together with the write if it rolls back.
+func Record(tx *gorm.DB, event events.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if event.OccurredAt.IsZero() {
+		event.OccurredAt = now
+	}
+
+	return tx.Create(&models.OutboxEvent{
+		Type:          event.Type,
+		ActorID:       event.ActorID,
+		Payload:       string(payload),
+		OccurredAt:    event.OccurredAt,
+		NextAttemptAt: now,
+	}).Error
+}
+
+// decode turns a recorded event back into the event it was recorded from.
+// Payloads of unknown event types are left as raw JSON.
+func decode(record models.OutboxEvent) (events.Event, error) {
+	event := events.Event{
+		ID:         record.ID,
+		Type:       record.Type,
+		ActorID:    record.ActorID,
+		OccurredAt: record.OccurredAt,
+		Payload:    json.RawMessage(record.Payload),
+	}
+
+	payloadType, ok := payloadTypes[record.Type]
+	if !ok {
+		return event, nil
+	}
+
+	payload := reflect.New(payloadType)
+	err := json.Unmarshal([]byte(record.Payload), payload.Interface())
+	if err != nil {
+		return event, err
+	}
+	event.Payload = payload.Elem().Interface()
+
+	return event, nil
+}
@@ -0,0 +1,71 @@
+package outbox
+
+import (
+	"encoding/json"
+	"time"
+
+	"tesjwt.go/events"
+)
+
+// Sink is somewhere the dispatcher sends events to. Every event is sent to
+// every sink at least once: a sink that fails gets the event again on the
+// next attempt, so sinks have to cope with duplicates, which they can tell
+// apart by the event ID.
+type Sink interface {
+	// Name identifies the sink in the outbox. It must not change once
+	// events were dispatched to the sink.
+	Name() string
+	Send(event events.Event) error
+}
+
+// Bus hands events to the in-process subscribers of the events package.
+type Bus struct{}
+
+func (Bus) Name() string {
+	return "bus"
+}
+
+func (Bus) Send(event events.Event) error {
+	events.Publish(event)
+	return nil
+}
+
+// Broker is a client of a message broker, such as a Kafka producer or an
+// AMQP channel.
+type Broker interface {
+	Publish(topic string, body []byte) error
+}
+
+// BrokerSink publishes events to a message broker as JSON, on a topic named
+// after the event type.
+type BrokerSink struct {
+	Broker      Broker
+	TopicPrefix string
+}
+
+type brokerMessage struct {
+	ID         uint        `json:"id"`
+	Type       string      `json:"type"`
+	ActorID    uint        `json:"actor_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+func (s BrokerSink) Name() string {
+	return "broker:" + s.TopicPrefix
+}
+
+func (s BrokerSink) Send(event events.Event) error {
+	body, err := json.Marshal(brokerMessage{
+		ID:         event.ID,
+		Type:       event.Type,
+		ActorID:    event.ActorID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	return s.Broker.Publish(s.TopicPrefix+event.Type, body)
+}
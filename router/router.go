@@ -30,6 +30,22 @@ func StartApp() *gin.Engine {
 		userRouter.POST("/register", controllers.UserRegister)
 		// Read
 		userRouter.POST("/login", controllers.UserLogin)
+		userRouter.GET("/:username", middlewares.Authentication(), controllers.FindProfile)
+		userRouter.GET("/:username/followers", middlewares.Authentication(), controllers.FindFollower)
+		userRouter.GET("/:username/following", middlewares.Authentication(), controllers.FindFollowing)
+		userRouter.GET("/me/follow-requests", middlewares.Authentication(), controllers.FindFollowRequest)
+		// Create
+		userRouter.POST("/:username/follow", middlewares.Authentication(), controllers.FollowUser)
+		userRouter.POST("/:username/block", middlewares.Authentication(), controllers.BlockUser)
+		userRouter.POST("/:username/mute", middlewares.Authentication(), controllers.MuteUser)
+		// Update
+		userRouter.POST("/me/follow-requests/:username/approve", middlewares.Authentication(), controllers.ApproveFollowRequest)
+		userRouter.PUT("/me/privacy", middlewares.Authentication(), controllers.UpdatePrivacy)
+		// Delete
+		userRouter.DELETE("/:username/follow", middlewares.Authentication(), controllers.UnfollowUser)
+		userRouter.DELETE("/:username/block", middlewares.Authentication(), controllers.UnblockUser)
+		userRouter.DELETE("/:username/mute", middlewares.Authentication(), controllers.UnmuteUser)
+		userRouter.DELETE("/me/follow-requests/:username", middlewares.Authentication(), controllers.RejectFollowRequest)
 	}
 
 	socialmediaRouter := r.Group("/socialmedia")
@@ -54,12 +70,75 @@ func StartApp() *gin.Engine {
 		photoRouter.POST("/", controllers.CreatePhoto)
 		// Read
 		photoRouter.GET("/", controllers.FindAllPhoto)
+		photoRouter.GET("/drafts", controllers.FindUnpublishedPhoto)
 		// Update
 		photoRouter.PUT("/:photoID", middlewares.Authorization(), controllers.UpdatePhoto)
 		// Delete
 		photoRouter.DELETE("/:photoID", middlewares.Authorization(), controllers.DeletePhoto)
 		// Read
 		photoRouter.GET("/:photoID", middlewares.Authorization(), controllers.FindPhotoById)
+		photoRouter.GET("/:photoID/comments", controllers.FindCommentByPhoto)
+		photoRouter.GET("/:photoID/comments/held", controllers.FindHeldComment)
+		photoRouter.GET("/:photoID/comment-settings", controllers.FindCommentSettings)
+		photoRouter.PUT("/:photoID/comment-settings", controllers.UpdateCommentSettings)
+		photoRouter.POST("/:photoID/like", controllers.LikePhoto)
+		photoRouter.DELETE("/:photoID/like", controllers.UnlikePhoto)
+		photoRouter.GET("/:photoID/likes", controllers.FindPhotoLike)
+		photoRouter.POST("/:photoID/save", controllers.SavePhoto)
+		photoRouter.DELETE("/:photoID/save", controllers.UnsavePhoto)
+		photoRouter.PUT("/:photoID/reactions/:emoji", controllers.AddPhotoReaction)
+		photoRouter.DELETE("/:photoID/reactions/:emoji", controllers.DeletePhotoReaction)
+	}
+
+	savedRouter := r.Group("/saved")
+	{
+		savedRouter.Use(middlewares.Authentication())
+		// Read
+		savedRouter.GET("/", controllers.FindSavedPhoto)
+		savedRouter.GET("/collections", controllers.FindSavedCollection)
+	}
+
+	notificationRouter := r.Group("/notifications")
+	{
+		notificationRouter.Use(middlewares.Authentication())
+		// Read
+		notificationRouter.GET("/", controllers.FindNotification)
+		notificationRouter.GET("/preferences", controllers.FindNotificationPreference)
+		// Update
+		notificationRouter.POST("/read", controllers.ReadNotification)
+		notificationRouter.PUT("/preferences", controllers.UpdateNotificationPreference)
+	}
+
+	conversationRouter := r.Group("/conversations")
+	{
+		conversationRouter.Use(middlewares.Authentication())
+		// Create
+		conversationRouter.POST("/", controllers.CreateConversation)
+		conversationRouter.POST("/:conversationID/messages", controllers.CreateMessage)
+		// Read
+		conversationRouter.GET("/", controllers.FindConversation)
+		conversationRouter.GET("/:conversationID/messages", controllers.FindMessage)
+		// Update
+		conversationRouter.POST("/:conversationID/read", controllers.ReadConversation)
+	}
+
+	storyRouter := r.Group("/stories")
+	{
+		storyRouter.Use(middlewares.Authentication())
+		// Create
+		storyRouter.POST("/", controllers.CreateStory)
+		// Read
+		storyRouter.GET("/feed", controllers.FindStoryFeed)
+		storyRouter.GET("/archive", controllers.FindStoryArchive)
+		storyRouter.GET("/:storyID", controllers.FindStoryById)
+		storyRouter.GET("/:storyID/viewers", controllers.FindStoryViewer)
+	}
+
+	feedRouter := r.Group("/feed")
+	{
+		feedRouter.Use(middlewares.Authentication())
+		// Read
+		feedRouter.GET("/", controllers.FindFeed)
 	}
 
 	commentRouter := r.Group("/comment")
@@ -75,8 +154,38 @@ func StartApp() *gin.Engine {
 		commentRouter.DELETE("/:commentID", middlewares.Authorization(), controllers.DeleteComment)
 		// Read
 		commentRouter.GET("/:commentID", middlewares.Authorization(), controllers.FindCommentById)
+		commentRouter.GET("/:commentID/revisions", controllers.FindCommentRevision)
+		commentRouter.PUT("/:commentID/status", controllers.UpdateCommentStatus)
+		commentRouter.PUT("/:commentID/reactions/:emoji", controllers.AddCommentReaction)
+		commentRouter.DELETE("/:commentID/reactions/:emoji", controllers.DeleteCommentReaction)
+	}
+
+	trashRouter := r.Group("/trash")
+	{
+		trashRouter.Use(middlewares.Authentication())
+		// Read
+		trashRouter.GET("/", controllers.FindTrash)
+		// Update
+		trashRouter.POST("/:type/:id/restore", controllers.RestoreTrash)
+	}
+
+	webhookRouter := r.Group("/webhooks")
+	{
+		webhookRouter.Use(middlewares.Authentication())
+		// Create
+		webhookRouter.POST("/", controllers.CreateWebhook)
+		// Read
+		webhookRouter.GET("/", controllers.FindWebhook)
+		webhookRouter.GET("/:webhookID/deliveries", controllers.FindWebhookDelivery)
+		// Update
+		webhookRouter.PUT("/:webhookID", controllers.UpdateWebhook)
+		webhookRouter.POST("/:webhookID/deliveries/:deliveryID/retry", controllers.RetryWebhookDelivery)
+		// Delete
+		webhookRouter.DELETE("/:webhookID", controllers.DeleteWebhook)
 	}
 
+	r.GET("/stream", middlewares.QueryToken(), middlewares.Authentication(), controllers.Stream)
+
 	r.GET("/docs/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
 	return r
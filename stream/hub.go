@@ -0,0 +1,195 @@
+package stream
+
+import (
+	"sync"
+	"time"
+)
+
+const (
+	// HistorySize is how many recent messages the hub keeps for clients
+	// that reconnect with the ID of the last message they got.
+	HistorySize = 1024
+	// HeartbeatInterval is how often idle connections are pinged so that
+	// proxies don't close them.
+	HeartbeatInterval = 25 * time.Second
+	// clientBuffer is how many messages may wait for a slow client before
+	// it is disconnected. It can catch up by reconnecting.
+	clientBuffer = 64
+)
+
+// Message types.
+const (
+	TypeNotification = "notification"
+	TypeComment      = "comment"
+	TypeFeed         = "feed"
+	TypeMessage      = "message"
+	// TypeResync tells a client that messages it missed are no longer
+	// kept, so it has to reload what it shows.
+	TypeResync = "resync"
+)
+
+// Message is pushed to the clients of one user, or to every client watching
+// one photo.
+type Message struct {
+	ID      uint64      `json:"id,omitempty"`
+	Type    string      `json:"type"`
+	Data    interface{} `json:"data,omitempty"`
+	userID  uint
+	photoID uint
+}
+
+// Client is one open stream of a user.
+type Client struct {
+	UserID   uint
+	Messages chan Message
+	watched  map[uint]bool
+}
+
+func (c *Client) wants(message Message) bool {
+	if message.userID != 0 {
+		return message.userID == c.UserID
+	}
+
+	return c.watched[message.photoID]
+}
+
+// Hub passes messages on to the open streams and keeps the recent ones
+// around for clients that reconnect.
+type Hub struct {
+	mu      sync.Mutex
+	lastID  uint64
+	history []Message
+	clients map[uint]map[*Client]bool
+}
+
+// NewHub creates an empty hub. Message IDs start at the current time in
+// milliseconds so they keep growing across restarts, and a client resuming
+// from before a restart is told to resync instead of silently missing
+// messages.
+func NewHub() *Hub {
+	return &Hub{
+		lastID:  uint64(time.Now().UnixMilli()),
+		clients: map[uint]map[*Client]bool{},
+	}
+}
+
+// Subscribe opens a stream for the user that also gets the comments on the
+// watched photos. When lastEventID is set, the messages the user missed
+// since are queued first, or a resync message when they are no longer kept.
+func (h *Hub) Subscribe(userID uint, watched []uint, lastEventID uint64) *Client {
+	client := &Client{
+		UserID:   userID,
+		Messages: make(chan Message, clientBuffer+HistorySize),
+		watched:  map[uint]bool{},
+	}
+	for _, photoID := range watched {
+		client.watched[photoID] = true
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if lastEventID != 0 {
+		h.replay(client, lastEventID)
+	}
+
+	if h.clients[userID] == nil {
+		h.clients[userID] = map[*Client]bool{}
+	}
+	h.clients[userID][client] = true
+
+	return client
+}
+
+func (h *Hub) replay(client *Client, lastEventID uint64) {
+	oldestID := h.lastID - uint64(len(h.history)) + 1
+	if lastEventID > h.lastID || lastEventID+1 < oldestID {
+		client.Messages <- Message{ID: h.lastID, Type: TypeResync}
+		return
+	}
+
+	for _, message := range h.history {
+		if message.ID > lastEventID && client.wants(message) {
+			client.Messages <- message
+		}
+	}
+}
+
+// Unsubscribe closes a stream.
+func (h *Hub) Unsubscribe(client *Client) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.remove(client)
+}
+
+func (h *Hub) remove(client *Client) {
+	if !h.clients[client.UserID][client] {
+		return
+	}
+
+	delete(h.clients[client.UserID], client)
+	if len(h.clients[client.UserID]) == 0 {
+		delete(h.clients, client.UserID)
+	}
+	close(client.Messages)
+}
+
+// Connected lists the users that have a stream open.
+func (h *Hub) Connected() []uint {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	userIDs := make([]uint, 0, len(h.clients))
+	for userID := range h.clients {
+		userIDs = append(userIDs, userID)
+	}
+
+	return userIDs
+}
+
+// SendToUser pushes a message to every stream of the user.
+func (h *Hub) SendToUser(userID uint, messageType string, data interface{}) {
+	h.send(Message{Type: messageType, Data: data, userID: userID})
+}
+
+// SendToWatchers pushes a message to every stream watching the photo.
+func (h *Hub) SendToWatchers(photoID uint, messageType string, data interface{}) {
+	h.send(Message{Type: messageType, Data: data, photoID: photoID})
+}
+
+func (h *Hub) send(message Message) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastID++
+	message.ID = h.lastID
+
+	h.history = append(h.history, message)
+	if len(h.history) > HistorySize {
+		h.history = h.history[len(h.history)-HistorySize:]
+	}
+
+	if message.userID != 0 {
+		h.deliver(h.clients[message.userID], message)
+		return
+	}
+
+	for _, clients := range h.clients {
+		h.deliver(clients, message)
+	}
+}
+
+func (h *Hub) deliver(clients map[*Client]bool, message Message) {
+	for client := range clients {
+		if !client.wants(message) {
+			continue
+		}
+
+		select {
+		case client.Messages <- message:
+		default:
+			h.remove(client)
+		}
+	}
+}
@@ -0,0 +1,88 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"golang.org/x/net/websocket"
+)
+
+// ServeSSE sends the messages of the client as Server-Sent Events until the
+// request is cancelled or the hub drops the client. Idle streams get a
+// comment line every HeartbeatInterval.
+func ServeSSE(w http.ResponseWriter, r *http.Request, client *Client) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming is not supported", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+	w.Header().Set("X-Accel-Buffering", "no")
+	w.WriteHeader(http.StatusOK)
+	flusher.Flush()
+
+	heartbeat := time.NewTicker(HeartbeatInterval)
+	defer heartbeat.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case message, ok := <-client.Messages:
+			if !ok {
+				return
+			}
+
+			data, err := json.Marshal(message.Data)
+			if err != nil {
+				return
+			}
+			fmt.Fprintf(w, "id: %d\nevent: %s\ndata: %s\n\n", message.ID, message.Type, data)
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+		}
+		flusher.Flush()
+	}
+}
+
+// ServeWebSocket upgrades the request to a WebSocket and sends the messages
+// of the client on it as JSON until either side goes away. Idle connections
+// get a ping message every HeartbeatInterval. Anything the client sends is
+// ignored.
+func ServeWebSocket(w http.ResponseWriter, r *http.Request, client *Client) {
+	websocket.Server{Handler: func(conn *websocket.Conn) {
+		closed := make(chan struct{})
+		go func() {
+			defer close(closed)
+			var ignored string
+			for websocket.Message.Receive(conn, &ignored) == nil {
+			}
+		}()
+
+		heartbeat := time.NewTicker(HeartbeatInterval)
+		defer heartbeat.Stop()
+
+		for {
+			var err error
+			select {
+			case <-closed:
+				return
+			case message, ok := <-client.Messages:
+				if !ok {
+					return
+				}
+				err = websocket.JSON.Send(conn, message)
+			case <-heartbeat.C:
+				err = websocket.JSON.Send(conn, Message{Type: "ping"})
+			}
+			if err != nil {
+				return
+			}
+		}
+	}}.ServeHTTP(w, r)
+}
@@ -0,0 +1,165 @@
+package webhooks
+
+import (
+	"bytes"
+	"fmt"
+	"log"
+	"net/http"
+	"strconv"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"tesjwt.go/models"
+)
+
+const (
+	// MaxAttempts is how many times a delivery is tried before it is
+	// marked dead.
+	MaxAttempts = 8
+	// BaseBackoff is how long the first retry waits. Every further retry
+	// waits twice as long as the one before, up to MaxBackoff.
+	BaseBackoff = 30 * time.Second
+	MaxBackoff  = 6 * time.Hour
+
+	// claimTimeout is how long a delivery picked up by one instance of the
+	// app is left alone by the others.
+	claimTimeout = time.Minute
+	batchSize    = 50
+)
+
+// Headers sent with every delivery.
+const (
+	EventHeader     = "X-Webhook-Event"
+	DeliveryHeader  = "X-Webhook-Delivery"
+	TimestampHeader = "X-Webhook-Timestamp"
+	SignatureHeader = "X-Webhook-Signature"
+)
+
+// Backoff is how long to wait before trying a delivery again after it
+// failed for the given number of times.
+func Backoff(attempts int) time.Duration {
+	backoff := BaseBackoff
+	for i := 1; i < attempts && backoff < MaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > MaxBackoff {
+		backoff = MaxBackoff
+	}
+
+	return backoff
+}
+
+// DeliverDue sends the deliveries that are due. Deliveries are claimed with
+// SKIP LOCKED, so several instances of the app can run it at once.
+func DeliverDue(db *gorm.DB, client *http.Client) error {
+	now := time.Now()
+	due := []models.WebhookDelivery{}
+	err := db.Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status = ? AND next_attempt_at <= ?", models.DeliveryPending, now).
+			Order("next_attempt_at").Limit(batchSize).Find(&due).Error
+		if err != nil || len(due) == 0 {
+			return err
+		}
+
+		ids := make([]uint, 0, len(due))
+		for _, delivery := range due {
+			ids = append(ids, delivery.ID)
+		}
+
+		return tx.Model(&models.WebhookDelivery{}).Where("id IN ?", ids).UpdateColumn("next_attempt_at", now.Add(claimTimeout)).Error
+	})
+	if err != nil || len(due) == 0 {
+		return err
+	}
+
+	webhookIDs := make([]uint, 0, len(due))
+	for _, delivery := range due {
+		webhookIDs = append(webhookIDs, delivery.WebhookID)
+	}
+
+	webhooks := []models.Webhook{}
+	err = db.Where("id IN ?", webhookIDs).Find(&webhooks).Error
+	if err != nil {
+		return err
+	}
+
+	byID := map[uint]models.Webhook{}
+	for _, webhook := range webhooks {
+		byID[webhook.ID] = webhook
+	}
+
+	for _, delivery := range due {
+		err = db.Model(&delivery).UpdateColumns(attempt(client, byID[delivery.WebhookID], delivery)).Error
+		if err != nil {
+			log.Println("error recording webhook delivery :", err)
+		}
+	}
+
+	return nil
+}
+
+// attempt sends a delivery once and returns the columns to update with the
+// outcome.
+func attempt(client *http.Client, webhook models.Webhook, delivery models.WebhookDelivery) map[string]interface{} {
+	now := time.Now()
+	attempts := delivery.Attempts + 1
+
+	statusCode, err := send(client, webhook, delivery)
+	if err == nil {
+		return map[string]interface{}{
+			"status":           models.DeliveryDelivered,
+			"attempts":         attempts,
+			"last_status_code": statusCode,
+			"last_error":       "",
+			"next_attempt_at":  nil,
+			"delivered_at":     now,
+		}
+	}
+
+	columns := map[string]interface{}{
+		"attempts":         attempts,
+		"last_status_code": statusCode,
+		"last_error":       err.Error(),
+		"next_attempt_at":  now.Add(Backoff(attempts)),
+	}
+	if attempts >= MaxAttempts || !webhook.Active {
+		columns["status"] = models.DeliveryDead
+		columns["next_attempt_at"] = nil
+	}
+
+	return columns
+}
+
+func send(client *http.Client, webhook models.Webhook, delivery models.WebhookDelivery) (int, error) {
+	if !webhook.Active {
+		return 0, fmt.Errorf("webhook is disabled")
+	}
+
+	body := []byte(delivery.Payload)
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(EventHeader, delivery.EventType)
+	req.Header.Set(DeliveryHeader, strconv.FormatUint(uint64(delivery.ID), 10))
+	req.Header.Set(TimestampHeader, timestamp)
+	req.Header.Set(SignatureHeader, "sha256="+Sign(webhook.Secret, timestamp, body))
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("endpoint responded with %s", resp.Status)
+	}
+
+	return resp.StatusCode, nil
+}
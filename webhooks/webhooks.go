@@ -0,0 +1,153 @@
+package webhooks
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+
+	"gorm.io/gorm"
+	"tesjwt.go/events"
+	"tesjwt.go/models"
+)
+
+// Payload is the JSON body POSTed to webhooks. Receivers can use the ID to
+// drop events they already handled.
+type Payload struct {
+	ID         uint        `json:"id"`
+	Type       string      `json:"type"`
+	ActorID    uint        `json:"actor_id"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
+
+// Sink is the outbox sink that queues a delivery to every webhook
+// subscribed to an event.
+type Sink struct {
+	DB *gorm.DB
+}
+
+func (s Sink) Name() string {
+	return "webhooks"
+}
+
+func (s Sink) Send(event events.Event) error {
+	for _, eventType := range models.WebhookEventTypes {
+		if eventType == event.Type {
+			return enqueue(s.DB, event)
+		}
+	}
+
+	return nil
+}
+
+// NewSecret generates the secret a webhook's deliveries are signed with.
+func NewSecret() string {
+	secret := make([]byte, 32)
+	rand.Read(secret)
+	return hex.EncodeToString(secret)
+}
+
+// Sign computes the signature of a delivery: the hex encoded HMAC-SHA256 of
+// the timestamp, a dot and the body, keyed with the webhook's secret.
+// Receivers should compute it themselves and reject deliveries whose
+// signature doesn't match or whose timestamp is too old.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp + "."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+func enqueue(db *gorm.DB, event events.Event) error {
+	userIDs, err := involvedUsers(db, event)
+	if err != nil {
+		return err
+	}
+
+	webhooks := []models.Webhook{}
+	err = db.Where("active AND (user_id IN ? OR user_id IN (SELECT id FROM users WHERE role = ?))", append(userIDs, 0), models.RoleAdmin).Find(&webhooks).Error
+	if err != nil {
+		return err
+	}
+
+	body, err := json.Marshal(Payload{
+		ID:         event.ID,
+		Type:       event.Type,
+		ActorID:    event.ActorID,
+		OccurredAt: event.OccurredAt,
+		Data:       event.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	deliveries := []models.WebhookDelivery{}
+	for _, webhook := range webhooks {
+		if !subscribed(webhook, event.Type) {
+			continue
+		}
+
+		deliveries = append(deliveries, models.WebhookDelivery{
+			WebhookID:     webhook.ID,
+			EventType:     event.Type,
+			Payload:       string(body),
+			Status:        models.DeliveryPending,
+			NextAttemptAt: &now,
+		})
+	}
+
+	if len(deliveries) == 0 {
+		return nil
+	}
+
+	return db.Create(&deliveries).Error
+}
+
+func subscribed(webhook models.Webhook, eventType string) bool {
+	for _, subscribedType := range webhook.EventTypes {
+		if subscribedType == eventType {
+			return true
+		}
+	}
+
+	return false
+}
+
+// involvedUsers lists the users an event is about: whoever caused it, and
+// the owner of the photo or the user it happened to.
+func involvedUsers(db *gorm.DB, event events.Event) ([]uint, error) {
+	userIDs := []uint{event.ActorID}
+
+	var photoID uint
+	switch payload := event.Payload.(type) {
+	case models.Photo:
+		userIDs = append(userIDs, payload.UserID)
+	case models.Comment:
+		photoID = payload.PhotoID
+	case models.Like:
+		photoID = payload.PhotoID
+	case models.Follow:
+		userIDs = append(userIDs, payload.FolloweeID)
+	case models.SocialMedia:
+		userIDs = append(userIDs, payload.UserID)
+	case models.Story:
+		userIDs = append(userIDs, payload.UserID)
+	case models.UserSummary:
+		userIDs = append(userIDs, payload.ID)
+	}
+
+	if photoID != 0 {
+		photo := models.Photo{}
+		err := db.Unscoped().Select("id", "user_id").First(&photo, photoID).Error
+		if err != nil {
+			return nil, err
+		}
+		userIDs = append(userIDs, photo.UserID)
+	}
+
+	return userIDs, nil
+}